@@ -1,10 +1,17 @@
 package main
 
 import (
+	"flag"
+
 	"ecstemplate/internal/game"
 )
 
 func main() {
+	seed := game.AddSeedFlag()
+	flag.Parse()
+
 	g := game.NewGame()
+	seed.Apply(g)
+	g.Initialize(80, 24)
 	g.Run()
 }
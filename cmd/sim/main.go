@@ -0,0 +1,80 @@
+// Command sim plays the game without a screen, as fast as it can, and prints how
+// the run went. It's for tuning tower and enemy numbers without playing by hand;
+// ECSTEMPLATE_TOWERS can name a towers file to use in place of the game's own.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"time"
+
+	"ecstemplate/internal/game"
+	"ecstemplate/internal/game/ui/headless"
+	"ecstemplate/internal/input"
+)
+
+func main() {
+	seed := game.AddSeedFlag()
+	waves := flag.Int("waves", 10, "number of waves to play")
+	limit := flag.Duration("limit", time.Hour, "longest simulation time to play for")
+	replayPath := flag.String("replay", "", "play the actions of a replay file")
+	scriptPath := flag.String("script", "", "play the commands of a script file")
+	workers := flag.Int("workers", 1, "systems to run at once, more than 1 can't be reproduced")
+	flag.Parse()
+
+	if *replayPath != "" && *scriptPath != "" {
+		log.Fatalf("Only one of -replay and -script can be given")
+	}
+
+	g := game.NewGame()
+	g.SetDisplayManager(&headless.DisplayManager{})
+	seed.Apply(g)
+	g.SetWorkers(*workers)
+
+	width, height := 80, 24
+	if *replayPath != "" {
+		replay, err := game.LoadReplay(*replayPath)
+		if err != nil {
+			log.Fatalf("Failed to load the replay: %v", err)
+		}
+		g.PlayReplay(replay)
+		width, height = replay.Width, replay.Height
+	}
+	if *replayPath == "" {
+		// Input always comes from a script, which without -script presses nothing and
+		// leaves the starting towers to defend on their own
+		script := &input.Script{}
+		if *scriptPath != "" {
+			var err error
+			if script, err = game.LoadScript(*scriptPath); err != nil {
+				log.Fatalf("Failed to load the script: %v", err)
+			}
+		}
+		g.PlayScript(script)
+	}
+	g.Initialize(width, height)
+
+	maxTicks := uint64(limit.Seconds() / game.SimulationStep)
+	printSummary(g.Simulate(*waves, maxTicks))
+}
+
+func printSummary(summary game.Summary) {
+	fmt.Printf("Seed:           %d\n", summary.Seed)
+	fmt.Printf("Waves cleared:  %d of %d started\n", summary.WavesCleared, summary.Waves)
+	fmt.Printf("Leaks:          %d\n", summary.Leaks)
+	fmt.Printf("Health:         %g\n", summary.Health)
+	fmt.Printf("Money:          %g\n", summary.Money)
+	fmt.Printf("Time:           %v\n", summary.Elapsed.Round(time.Millisecond))
+	if summary.GameOver {
+		fmt.Println("Result:         game over")
+	}
+
+	fmt.Println("Kills by tower:")
+	towerTypes := slices.Sorted(maps.Keys(summary.Kills))
+	for _, towerType := range towerTypes {
+		fmt.Printf("  %-13s %d\n", towerType, summary.Kills[towerType])
+	}
+}
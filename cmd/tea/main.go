@@ -1,12 +1,16 @@
 package main
 
 import (
+	"flag"
+	"fmt"
+	"os"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 
 	"ecstemplate/internal/game"
 	"ecstemplate/internal/game/ui/teaui"
+	"ecstemplate/internal/input"
 )
 
 type TickMsg struct {
@@ -19,9 +23,30 @@ type GameModel struct {
 	frameRate time.Duration
 }
 
-func NewGameModel() *GameModel {
+// NewGameModel starts a game from the seed, or plays back the replay if there is one.
+// Without either a seed is picked. A script, if given, plays the game instead of the keys.
+func NewGameModel(
+	seed *game.SeedFlag,
+	replay *input.Replay,
+	script *input.Script,
+	record bool,
+) *GameModel {
 	g := game.NewGame()
-	g.Initialize(80, 10)
+	width, height := 80, 10
+	if replay != nil {
+		g.PlayReplay(replay)
+		width, height = replay.Width, replay.Height
+	} else {
+		seed.Apply(g)
+	}
+	if script != nil {
+		g.PlayScript(script)
+	}
+	g.Initialize(width, height)
+	if record {
+		g.StartRecording()
+	}
+
 	return &GameModel{
 		game:      g,
 		lastTick:  time.Now(),
@@ -52,9 +77,10 @@ func (m GameModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, m.tick()
 
 	case tea.KeyMsg:
-		// Queue the key in the input manager
-		inputManager := m.game.GetInputManager().(*teaui.InputManager)
-		inputManager.QueueKey(msg.String())
+		// Queue the key in the input manager, unless a replay or script is playing
+		if inputManager, ok := m.game.GetInputManager().(*teaui.InputManager); ok {
+			inputManager.QueueKey(msg.String())
+		}
 
 		// Check for quit
 		if msg.String() == "q" || msg.String() == "ctrl+c" {
@@ -66,6 +92,7 @@ func (m GameModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		displayManager := m.game.GetDisplayManager().(*teaui.DisplayManager)
 		displayManager.Resize(msg.Width, msg.Height)
+		m.game.RequestRedraw()
 	}
 
 	return m, nil
@@ -73,13 +100,49 @@ func (m GameModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m GameModel) View() string {
 	displayManager := m.game.GetDisplayManager().(*teaui.DisplayManager)
-	buffer := displayManager.GetBuffer()
-	return buffer.String()
+	return displayManager.View()
 }
 
 func main() {
-	p := tea.NewProgram(NewGameModel(), tea.WithAltScreen())
+	seed := game.AddSeedFlag()
+	record := flag.String("record", "", "write a replay of the game to this file on quitting")
+	replayPath := flag.String("replay", "", "play back a replay file instead of reading keys")
+	scriptPath := flag.String("script", "", "play a script file instead of reading keys")
+	flag.Parse()
+
+	if *replayPath != "" && *scriptPath != "" {
+		fmt.Fprintln(os.Stderr, "Only one of -replay and -script can be given")
+		os.Exit(1)
+	}
+
+	var replay *input.Replay
+	if *replayPath != "" {
+		var err error
+		if replay, err = game.LoadReplay(*replayPath); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+
+	var script *input.Script
+	if *scriptPath != "" {
+		var err error
+		if script, err = game.LoadScript(*scriptPath); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+
+	model := NewGameModel(seed, replay, script, *record != "")
+	p := tea.NewProgram(model, tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
 		panic(err)
 	}
+
+	if *record != "" {
+		if err := model.game.SaveRecording(*record); err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to save the replay:", err)
+			os.Exit(1)
+		}
+	}
 }
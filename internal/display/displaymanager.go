@@ -1,6 +1,8 @@
 package display
 
 import (
+	"time"
+
 	"ecstemplate/internal/game/components"
 	"ecstemplate/pkg/ecs"
 )
@@ -35,10 +37,15 @@ type DisplayManager interface {
 
 // GameInfo contains the information needed to render the UI
 type GameInfo struct {
-	PlayerHealth float64
-	PlayerMoney  float64
-	CurrentWave  int
-	WaveProgress float64
-	GameOver     bool
-	Message      string
+	PlayerHealth  float64
+	PlayerMoney   float64
+	CurrentWave   int
+	WaveProgress  float64
+	WaveCountdown time.Duration // Build time left before the wave starts, 0 once it has
+	EarlyBonus    float64       // Money for calling the wave now
+	GameOver      bool
+	Message       string
+	Speed         float64 // Game speed multiplier
+	Paused        bool
+	Seed          uint64 // Seed the run's random numbers started from, to reproduce it
 }
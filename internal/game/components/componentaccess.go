@@ -2,6 +2,12 @@ package components
 
 import "ecstemplate/pkg/ecs"
 
+// ComponentAccess reads components by entity, for code that hasn't moved over to
+// ecs.Store yet. Systems use stores, and new components only get a store, not a getter.
+//
+// The getters don't record changes. Code that modifies a component through one must
+// call ComponentManager.MarkChanged after, or change detection won't see it and the
+// screen won't be redrawn.
 type ComponentAccess struct {
 	world *ecs.World
 }
@@ -24,18 +30,10 @@ func GetComponentT[T ecs.ComponentInterface](
 	return component.(T), true
 }
 
-func (c *ComponentAccess) GetDisplayComponent(entity ecs.Entity) (*DisplayComponent, bool) {
-	return GetComponentT[*DisplayComponent](c.world, entity, Display)
-}
-
 func (c *ComponentAccess) GetCursorComponent(entity ecs.Entity) (*CursorComponent, bool) {
 	return GetComponentT[*CursorComponent](c.world, entity, Cursor)
 }
 
-func (c *ComponentAccess) GetGameStateComponent(entity ecs.Entity) (*GameStateComponent, bool) {
-	return GetComponentT[*GameStateComponent](c.world, entity, GameState)
-}
-
 func (c *ComponentAccess) GetPlayerComponent(entity ecs.Entity) (*PlayerComponent, bool) {
 	return GetComponentT[*PlayerComponent](c.world, entity, Player)
 }
@@ -1,24 +1,25 @@
 package components
 
 import (
+	"slices"
 	"time"
 
 	"ecstemplate/pkg/ecs"
 )
 
 const (
-	Display           ecs.ComponentType = "display"
 	Cursor            ecs.ComponentType = "cursor"
-	GameState         ecs.ComponentType = "game_state"
 	Player            ecs.ComponentType = "player"
 	Enemy             ecs.ComponentType = "enemy"
 	BoundingBox       ecs.ComponentType = "bounding_box"
 	Position          ecs.ComponentType = "position"
+	PreviousPosition  ecs.ComponentType = "previous_position"
 	Health            ecs.ComponentType = "health"
 	Velocity          ecs.ComponentType = "velocity"
 	Tower             ecs.ComponentType = "tower"
 	TowerTemplate     ecs.ComponentType = "tower_template"
 	Projectile        ecs.ComponentType = "projectile"
+	ProjectileSpec    ecs.ComponentType = "projectile_spec"
 	Path              ecs.ComponentType = "path"
 	PathFollow        ecs.ComponentType = "path_follow"
 	Wallet            ecs.ComponentType = "wallet"
@@ -26,17 +27,9 @@ const (
 	ShootIntent       ecs.ComponentType = "shoot_intent"
 	BuyIntent         ecs.ComponentType = "buy_intent"
 	CreateTowerIntent ecs.ComponentType = "create_tower_intent"
+	CallWaveIntent    ecs.ComponentType = "call_wave_intent"
 )
 
-type DisplayComponent struct {
-	ecs.Component
-	Width, Height int
-}
-
-func (c DisplayComponent) GetType() ecs.ComponentType {
-	return Display
-}
-
 type CursorComponent struct {
 	ecs.Component
 }
@@ -45,15 +38,6 @@ func (c CursorComponent) GetType() ecs.ComponentType {
 	return Cursor
 }
 
-type GameStateComponent struct {
-	ecs.Component
-	GameOver bool
-}
-
-func (c GameStateComponent) GetType() ecs.ComponentType {
-	return GameState
-}
-
 type PlayerComponent struct {
 	ecs.Component
 }
@@ -64,15 +48,23 @@ func (c PlayerComponent) GetType() ecs.ComponentType {
 
 type EnemyComponent struct {
 	ecs.Component
-	Type   string
-	Speed  float64
-	Reward float64
+	Type      string
+	Speed     float64
+	Reward    float64
+	Damage    float64  // Health the player loses if it gets through
+	Abilities []string // Special abilities from its archetype
+	Wave      int      // Wave the enemy was spawned in, 0 if it wasn't part of one
 }
 
 func (c EnemyComponent) GetType() ecs.ComponentType {
 	return Enemy
 }
 
+// HasAbility reports whether the enemy has the special ability
+func (c EnemyComponent) HasAbility(ability string) bool {
+	return slices.Contains(c.Abilities, ability)
+}
+
 // Bounding box pairs with the Position component to define the size of an entity. Position is the center of the bounding box
 type BoundingBoxComponent struct {
 	ecs.Component
@@ -92,6 +84,17 @@ func (c PositionComponent) GetType() ecs.ComponentType {
 	return Position
 }
 
+// PreviousPosition is where a moving entity was before the last simulation step, so
+// it can be drawn part way between that and its Position
+type PreviousPositionComponent struct {
+	ecs.Component
+	X, Y float64
+}
+
+func (c PreviousPositionComponent) GetType() ecs.ComponentType {
+	return PreviousPosition
+}
+
 type HealthComponent struct {
 	ecs.Component
 	Current, Max float64
@@ -110,11 +113,13 @@ func (c VelocityComponent) GetType() ecs.ComponentType {
 	return Velocity
 }
 
+// TowerComponent pairs with a ProjectileSpec component, for what the tower fires
 type TowerComponent struct {
 	ecs.Component
-	Cooldown      time.Duration
-	LastFired     time.Time
-	Damage, Range float64
+	Type      TowerType
+	Cooldown  time.Duration
+	LastFired time.Duration // Simulation time, see ecs.Clock
+	Range     float64
 }
 
 func (c TowerComponent) GetType() ecs.ComponentType {
@@ -123,16 +128,25 @@ func (c TowerComponent) GetType() ecs.ComponentType {
 
 type TowerType string
 
+// The tower types the game ships with. More can be added in the data package's
+// towers.json without a constant here.
 const (
 	BasicTower  TowerType = "basic"
 	MediumTower TowerType = "medium"
 	HeavyTower  TowerType = "heavy"
 )
 
+// TowerTemplateComponent pairs with Tower and ProjectileSpec components on an entity
+// the tower factory copies to build towers of the type. Templates aren't Renderable,
+// so they don't shoot.
 type TowerTemplateComponent struct {
 	ecs.Component
-	Type TowerType
-	Cost float64
+	Type     TowerType
+	Name     string
+	Cost     float64
+	Symbol   string
+	Color    string      // Hex colour, e.g. "#FF0000", or empty for the default
+	Upgrades []TowerType // Types the tower can be upgraded to
 }
 
 func (c TowerTemplateComponent) GetType() ecs.ComponentType {
@@ -141,14 +155,33 @@ func (c TowerTemplateComponent) GetType() ecs.ComponentType {
 
 type ProjectileComponent struct {
 	ecs.Component
-	TargetEntity  ecs.Entity
+	TargetEntity  ecs.Entity // May have been removed since, check EntityManager.IsAlive
+	Shooter       ecs.Entity // Tower that fired it
 	Damage, Speed float64
+	Pierce        int           // Enemies it can still pass through before it's removed
+	Expires       time.Duration // Simulation time it's removed at, or 0 if it never expires
+	Hits          []ecs.Entity  // Enemies it has hit, which it passes through from then on
 }
 
 func (c ProjectileComponent) GetType() ecs.ComponentType {
 	return Projectile
 }
 
+// ProjectileSpecComponent describes the projectiles a tower fires
+type ProjectileSpecComponent struct {
+	ecs.Component
+	Kind          string
+	Damage, Speed float64
+	Symbol        string
+	Size          float64       // Width and height of its bounding box
+	Lifetime      time.Duration // Time until it's removed, or 0 to fly until it leaves the map
+	Pierce        int           // Enemies it passes through before it's removed
+}
+
+func (c ProjectileSpecComponent) GetType() ecs.ComponentType {
+	return ProjectileSpec
+}
+
 type PathComponent struct {
 	ecs.Component
 	ID        string
@@ -181,6 +214,7 @@ func (c WalletComponent) GetType() ecs.ComponentType {
 type RenderableComponent struct {
 	ecs.Component
 	Symbol string
+	Color  string // Hex colour, e.g. "#FF0000", or empty for the default
 }
 
 func (c RenderableComponent) GetType() ecs.ComponentType {
@@ -189,7 +223,7 @@ func (c RenderableComponent) GetType() ecs.ComponentType {
 
 type ShootIntentComponent struct {
 	ecs.Component
-	Shooter, Target ecs.Entity
+	Shooter, Target ecs.Entity // Either may have been removed since, check EntityManager.IsAlive
 }
 
 func (c ShootIntentComponent) GetType() ecs.ComponentType {
@@ -215,19 +249,28 @@ func (c CreateTowerIntentComponent) GetType() ecs.ComponentType {
 	return CreateTowerIntent
 }
 
+// CallWaveIntentComponent asks for the next wave to start before its build time is up
+type CallWaveIntentComponent struct {
+	ecs.Component
+}
+
+func (c CallWaveIntentComponent) GetType() ecs.ComponentType {
+	return CallWaveIntent
+}
+
 var ComponentTypes = []ecs.ComponentType{
-	Display,
 	Cursor,
-	GameState,
 	Player,
 	Enemy,
 	BoundingBox,
 	Position,
+	PreviousPosition,
 	Health,
 	Velocity,
 	Tower,
 	TowerTemplate,
 	Projectile,
+	ProjectileSpec,
 	Path,
 	PathFollow,
 	Wallet,
@@ -235,4 +278,30 @@ var ComponentTypes = []ecs.ComponentType{
 	ShootIntent,
 	BuyIntent,
 	CreateTowerIntent,
+	CallWaveIntent,
+}
+
+// Register records the concrete type of every component type, so worlds using
+// them can be saved and loaded
+func Register(world *ecs.World) {
+	ecs.RegisterComponent[CursorComponent](world)
+	ecs.RegisterComponent[PlayerComponent](world)
+	ecs.RegisterComponent[EnemyComponent](world)
+	ecs.RegisterComponent[BoundingBoxComponent](world)
+	ecs.RegisterComponent[PositionComponent](world)
+	ecs.RegisterComponent[PreviousPositionComponent](world)
+	ecs.RegisterComponent[HealthComponent](world)
+	ecs.RegisterComponent[VelocityComponent](world)
+	ecs.RegisterComponent[TowerComponent](world)
+	ecs.RegisterComponent[TowerTemplateComponent](world)
+	ecs.RegisterComponent[ProjectileComponent](world)
+	ecs.RegisterComponent[ProjectileSpecComponent](world)
+	ecs.RegisterComponent[PathComponent](world)
+	ecs.RegisterComponent[PathFollowComponent](world)
+	ecs.RegisterComponent[WalletComponent](world)
+	ecs.RegisterComponent[RenderableComponent](world)
+	ecs.RegisterComponent[ShootIntentComponent](world)
+	ecs.RegisterComponent[BuyIntentComponent](world)
+	ecs.RegisterComponent[CreateTowerIntentComponent](world)
+	ecs.RegisterComponent[CallWaveIntentComponent](world)
 }
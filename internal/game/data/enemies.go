@@ -0,0 +1,87 @@
+package data
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"ecstemplate/internal/game/resources"
+)
+
+//go:embed enemies.json
+var enemiesJSON []byte
+
+// enemyFile is an enemy archetype as written in enemies.json
+type enemyFile struct {
+	Speed     float64  `json:"speed"`
+	Health    float64  `json:"health"`
+	Reward    float64  `json:"reward"`
+	Damage    float64  `json:"damage"`
+	Width     float64  `json:"width"`  // 1 if left out
+	Height    float64  `json:"height"` // 1 if left out
+	Symbol    string   `json:"symbol"`
+	Color     string   `json:"color"`
+	Abilities []string `json:"abilities"`
+}
+
+// Enemies returns the enemy archetypes the game is played with, by type
+func Enemies() (map[string]resources.EnemyArchetype, error) {
+	return ParseEnemies(bytes.NewReader(enemiesJSON))
+}
+
+// ParseEnemies reads enemy archetypes in the format of enemies.json, an object with
+// an archetype for each enemy type
+func ParseEnemies(in io.Reader) (map[string]resources.EnemyArchetype, error) {
+	var files map[string]enemyFile
+	decoder := json.NewDecoder(in)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&files); err != nil {
+		return nil, fmt.Errorf("reading enemies: %w", err)
+	}
+
+	enemies := make(map[string]resources.EnemyArchetype, len(files))
+	for enemyType, file := range files {
+		if file.Speed <= 0 || file.Health <= 0 || file.Symbol == "" {
+			return nil, fmt.Errorf(
+				"enemy %q needs a speed and health above 0, and a symbol", enemyType,
+			)
+		}
+		if file.Width == 0 {
+			file.Width = 1
+		}
+		if file.Height == 0 {
+			file.Height = 1
+		}
+		enemies[enemyType] = resources.EnemyArchetype{
+			Type:      enemyType,
+			Speed:     file.Speed,
+			Health:    file.Health,
+			Reward:    file.Reward,
+			Damage:    file.Damage,
+			Width:     file.Width,
+			Height:    file.Height,
+			Symbol:    file.Symbol,
+			Color:     file.Color,
+			Abilities: file.Abilities,
+		}
+	}
+	return enemies, nil
+}
+
+// CheckWaves makes sure every enemy the waves spawn has an archetype
+func CheckWaves(
+	waves []resources.WaveDefinition,
+	enemies map[string]resources.EnemyArchetype,
+) error {
+	for i, wave := range waves {
+		for j, group := range wave.Groups {
+			if _, found := enemies[group.Enemy]; !found {
+				return fmt.Errorf("wave %d group %d has an unknown enemy type %q",
+					i+1, j+1, group.Enemy)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package data
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEnemiesLoad(t *testing.T) {
+	enemies, err := Enemies()
+	if err != nil {
+		t.Fatalf("Expected the game's enemies to load, got %v", err)
+	}
+	waves, err := Waves()
+	if err != nil {
+		t.Fatalf("Expected the game's waves to load, got %v", err)
+	}
+	if err := CheckWaves(waves, enemies); err != nil {
+		t.Errorf("Expected every wave's enemies to exist, got %v", err)
+	}
+
+	basic := enemies["basic"]
+	if basic.Type != "basic" || basic.Width != 1 || basic.Height != 1 || basic.Damage != 1 {
+		t.Errorf("Expected a 1 by 1 basic enemy that does 1 damage, got %+v", basic)
+	}
+}
+
+func TestParseEnemiesRejectsBadEnemies(t *testing.T) {
+	tests := map[string]string{
+		`{"slow": {"speed": 0, "health": 1, "symbol": "s"}}`: "needs a speed",
+		`{"slow": {"speed": 1, "health": 1}}`:                "and a symbol",
+		`{"slow": {"speed": 1, "armour": 1, "symbol": "s"}}`: "unknown field",
+		`[]`: "reading enemies",
+	}
+	for enemies, want := range tests {
+		_, err := ParseEnemies(strings.NewReader(enemies))
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %s to fail with %q, got %v", enemies, want, err)
+		}
+	}
+}
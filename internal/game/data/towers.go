@@ -0,0 +1,149 @@
+package data
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"ecstemplate/internal/game/components"
+	"ecstemplate/internal/game/resources"
+)
+
+//go:embed towers.json
+var towersJSON []byte
+
+// towerFile is a tower as written in towers.json, with times in seconds
+type towerFile struct {
+	Type       components.TowerType   `json:"type"`
+	Name       string                 `json:"name"`
+	Hotkey     string                 `json:"hotkey"`
+	Cost       float64                `json:"cost"`
+	Cooldown   float64                `json:"cooldown"`
+	Range      float64                `json:"range"`
+	Projectile projectileFile         `json:"projectile"`
+	Symbol     string                 `json:"symbol"`
+	Color      string                 `json:"color"`
+	Upgrades   []components.TowerType `json:"upgrades"`
+}
+
+// projectileFile is what a tower fires, as written in towers.json
+type projectileFile struct {
+	Kind     string  `json:"kind"`
+	Damage   float64 `json:"damage"`
+	Speed    float64 `json:"speed"`
+	Symbol   string  `json:"symbol"`
+	Size     float64 `json:"size"`     // 1 if left out
+	Lifetime float64 `json:"lifetime"` // Seconds, or 0 to fly until it leaves the map
+	Pierce   int     `json:"pierce"`   // Enemies it passes through
+}
+
+// TowersEnv names the environment variable that points the game at a towers file of
+// its own, for trying out tower numbers without rebuilding
+const TowersEnv = "ECSTEMPLATE_TOWERS"
+
+// Towers returns the towers the player can build, in the order they're listed.
+// They're read from the file named by TowersEnv if it's set, or towers.json if not.
+func Towers() ([]resources.TowerDefinition, error) {
+	if path := os.Getenv(TowersEnv); path != "" {
+		return LoadTowers(path)
+	}
+	return ParseTowers(bytes.NewReader(towersJSON))
+}
+
+// LoadTowers reads tower definitions from a file in the format of towers.json
+func LoadTowers(path string) ([]resources.TowerDefinition, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	towers, err := ParseTowers(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return towers, nil
+}
+
+// ParseTowers reads tower definitions in the format of towers.json
+func ParseTowers(in io.Reader) ([]resources.TowerDefinition, error) {
+	var files []towerFile
+	decoder := json.NewDecoder(in)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&files); err != nil {
+		return nil, fmt.Errorf("reading towers: %w", err)
+	}
+
+	towers := make([]resources.TowerDefinition, 0, len(files))
+	types := make(map[components.TowerType]bool)
+	hotkeys := make(map[string]bool)
+	for i, file := range files {
+		switch {
+		case file.Type == "" || file.Symbol == "":
+			return nil, fmt.Errorf("tower %d needs a type and a symbol", i+1)
+		case types[file.Type]:
+			return nil, fmt.Errorf("tower type %q is defined twice", file.Type)
+		case file.Hotkey != "" && hotkeys[file.Hotkey]:
+			return nil, fmt.Errorf("tower %q has hotkey %q, which is already used",
+				file.Type, file.Hotkey)
+		case file.Cooldown <= 0 || file.Range <= 0 || file.Cost < 0:
+			return nil, fmt.Errorf(
+				"tower %q needs a cooldown and range above 0, and a cost of at least 0",
+				file.Type,
+			)
+		case file.Projectile.Speed <= 0 || file.Projectile.Symbol == "":
+			return nil, fmt.Errorf(
+				"tower %q needs a projectile with a speed above 0 and a symbol", file.Type,
+			)
+		case file.Projectile.Lifetime < 0 || file.Projectile.Pierce < 0:
+			return nil, fmt.Errorf(
+				"tower %q has a projectile with a negative lifetime or pierce", file.Type,
+			)
+		}
+		types[file.Type] = true
+		hotkeys[file.Hotkey] = true
+
+		name := file.Name
+		if name == "" {
+			name = string(file.Type)
+		}
+		size := file.Projectile.Size
+		if size == 0 {
+			size = 1
+		}
+		towers = append(towers, resources.TowerDefinition{
+			Type:     file.Type,
+			Name:     name,
+			Hotkey:   file.Hotkey,
+			Cost:     file.Cost,
+			Cooldown: seconds(file.Cooldown),
+			Range:    file.Range,
+			Projectile: components.ProjectileSpecComponent{
+				Kind:     file.Projectile.Kind,
+				Damage:   file.Projectile.Damage,
+				Speed:    file.Projectile.Speed,
+				Symbol:   file.Projectile.Symbol,
+				Size:     size,
+				Lifetime: seconds(file.Projectile.Lifetime),
+				Pierce:   file.Projectile.Pierce,
+			},
+			Symbol:   file.Symbol,
+			Color:    file.Color,
+			Upgrades: file.Upgrades,
+		})
+	}
+
+	// Upgrades can point at towers further down the list
+	for _, tower := range towers {
+		for _, upgrade := range tower.Upgrades {
+			if !types[upgrade] {
+				return nil, fmt.Errorf("tower %q upgrades to unknown tower type %q",
+					tower.Type, upgrade)
+			}
+		}
+	}
+	return towers, nil
+}
@@ -0,0 +1,107 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"ecstemplate/internal/game/components"
+)
+
+func TestTowersLoad(t *testing.T) {
+	towers, err := Towers()
+	if err != nil {
+		t.Fatalf("Expected the game's towers to load, got %v", err)
+	}
+
+	var types []components.TowerType
+	for _, tower := range towers {
+		types = append(types, tower.Type)
+	}
+	want := []components.TowerType{
+		components.BasicTower,
+		components.MediumTower,
+		components.HeavyTower,
+	}
+	if !slices.Equal(types, want) {
+		t.Fatalf("Expected the towers %v, got %v", want, types)
+	}
+	if medium := towers[1]; medium.Cost != 10 || medium.Cooldown != time.Second/2 {
+		t.Errorf("Expected the medium tower to cost 10 and fire every 500ms, got %+v", medium)
+	}
+	if basic := towers[0].Projectile; basic.Size != 1 || basic.Lifetime != 2*time.Second {
+		t.Errorf("Expected basic towers to fire 1 by 1 projectiles that last 2s, got %+v", basic)
+	}
+}
+
+func TestTowersLoadFromTheFileGiven(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "towers.json")
+	towers := `[{"type": "sniper", "name": "Sniper", "hotkey": "1", "cost": 30,` +
+		` "cooldown": 3, "range": 20, "symbol": "S",` +
+		` "projectile": {"damage": 10, "speed": 40, "symbol": "*"}}]`
+	if err := os.WriteFile(path, []byte(towers), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(TowersEnv, path)
+	loaded, err := Towers()
+	if err != nil {
+		t.Fatalf("Expected the towers in %s to load, got %v", path, err)
+	}
+	if len(loaded) != 1 || loaded[0].Type != "sniper" || loaded[0].Range != 20 {
+		t.Errorf("Expected just the sniper tower, got %+v", loaded)
+	}
+
+	// An empty variable falls back on the game's own towers
+	t.Setenv(TowersEnv, "")
+	if loaded, err := Towers(); err != nil || len(loaded) != 3 {
+		t.Errorf("Expected the game's 3 towers, got %d and %v", len(loaded), err)
+	}
+}
+
+func TestLoadTowersFailures(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := LoadTowers(filepath.Join(dir, "missing.json")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected a missing towers file to fail as missing, got %v", err)
+	}
+
+	path := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(path, []byte(`[{"type": "a"}]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadTowers(path); err == nil || !strings.Contains(err.Error(), path) {
+		t.Errorf("Expected a bad towers file to fail naming %s, got %v", path, err)
+	}
+}
+
+func TestParseTowersRejectsBadTowers(t *testing.T) {
+	tower := func(towerType, hotkey string, upgrades ...string) string {
+		return fmt.Sprintf(
+			`{"type": %q, "hotkey": %q, "cooldown": 1, "range": 1, "symbol": "T",`+
+				` "projectile": {"speed": 1, "symbol": "o"}, "upgrades": [%s]}`,
+			towerType, hotkey, strings.Join(upgrades, ","),
+		)
+	}
+	tests := map[string]string{
+		`[{"type": "a", "cooldown": 1, "range": 1}]`:                "needs a type and a symbol",
+		`[{"type": "a", "range": 1, "symbol": "T"}]`:                "needs a cooldown and range",
+		`[{"type": "a", "damage": "lots"}]`:                         "reading towers",
+		"[" + tower("a", "1") + "," + tower("a", "2") + "]":         "defined twice",
+		"[" + tower("a", "1") + "," + tower("b", "1") + "]":         "already used",
+		"[" + tower("a", "1", `"b"`) + "]":                          "unknown tower type",
+		`[{"type": "a", "cooldown": 1, "range": 1, "symbol": "T"}]`: "needs a projectile",
+		`[{"type": "a", "damage": 1}]`:                              "unknown field",
+	}
+	for towers, want := range tests {
+		_, err := ParseTowers(strings.NewReader(towers))
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %s to fail with %q, got %v", towers, want, err)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+// Package data holds the game's numbers, kept out of the code so they can be tuned
+package data
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"ecstemplate/internal/game/resources"
+)
+
+//go:embed waves.json
+var wavesJSON []byte
+
+// waveFile is a wave as written in waves.json, with times in seconds
+type waveFile struct {
+	Delay  float64 `json:"delay"`
+	Groups []struct {
+		Enemy   string  `json:"enemy"`
+		Count   int     `json:"count"`
+		Spacing float64 `json:"spacing"`
+		Path    string  `json:"path"`
+		Delay   float64 `json:"delay"`
+	} `json:"groups"`
+}
+
+// Waves returns the waves the game is played with
+func Waves() ([]resources.WaveDefinition, error) {
+	return ParseWaves(bytes.NewReader(wavesJSON))
+}
+
+// ParseWaves reads wave definitions in the format of waves.json
+func ParseWaves(in io.Reader) ([]resources.WaveDefinition, error) {
+	var files []waveFile
+	decoder := json.NewDecoder(in)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&files); err != nil {
+		return nil, fmt.Errorf("reading waves: %w", err)
+	}
+
+	waves := make([]resources.WaveDefinition, 0, len(files))
+	for i, file := range files {
+		if len(file.Groups) == 0 {
+			return nil, fmt.Errorf("wave %d has no groups", i+1)
+		}
+		wave := resources.WaveDefinition{Delay: seconds(file.Delay)}
+		for j, group := range file.Groups {
+			if group.Enemy == "" || group.Path == "" || group.Count < 1 {
+				return nil, fmt.Errorf(
+					"wave %d group %d needs an enemy, a path and a count of at least 1",
+					i+1, j+1,
+				)
+			}
+			wave.Groups = append(wave.Groups, resources.SpawnGroup{
+				Enemy:   group.Enemy,
+				Count:   group.Count,
+				Spacing: seconds(group.Spacing),
+				Path:    group.Path,
+				Delay:   seconds(group.Delay),
+			})
+		}
+		waves = append(waves, wave)
+	}
+	return waves, nil
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,38 @@
+package data
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWavesLoad(t *testing.T) {
+	waves, err := Waves()
+	if err != nil {
+		t.Fatalf("Expected the game's waves to load, got %v", err)
+	}
+	if len(waves) == 0 {
+		t.Fatalf("Expected the game to have waves")
+	}
+
+	first := waves[0].Groups[0]
+	if first.Enemy != "basic" || first.Spacing != 2*time.Second {
+		t.Errorf("Expected the first wave to start with basic enemies 2s apart, got %+v", first)
+	}
+}
+
+func TestParseWavesRejectsBadWaves(t *testing.T) {
+	tests := map[string]string{
+		`[{"delay": 1, "groups": []}]`:                    "wave 1 has no groups",
+		`[{"groups": [{"enemy": "basic", "count": 1}]}]`:  "wave 1 group 1 needs",
+		`[{"groups": [{"enemy": "basic", "speed": 1}]}]`:  "unknown field",
+		`[{"groups": [{"enemy": "basic", "path": "p"}]}]`: "count of at least 1",
+		`{"delay": 1}`: "reading waves",
+	}
+	for waves, want := range tests {
+		_, err := ParseWaves(strings.NewReader(waves))
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %s to fail with %q, got %v", waves, want, err)
+		}
+	}
+}
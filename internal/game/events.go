@@ -5,67 +5,70 @@ import (
 
 	"ecstemplate/internal/game/components"
 	"ecstemplate/internal/game/events"
+	"ecstemplate/internal/game/resources"
 	"ecstemplate/pkg/ecs"
 )
 
-func (g *Game) towerCreatedEventHandler(event ecs.EventInterface) {
+func (g *Game) towerCreatedEventHandler(event events.TowerCreatedEvent) {
 
 }
 
-func (g *Game) enemyKilledEventHandler(event ecs.EventInterface) {
-
+func (g *Game) enemyKilledEventHandler(event events.EnemyKilledEvent) {
+	// Credit the kill to the type of tower that made it
+	tower, found := g.componentAccess.GetTowerComponent(event.Killer)
+	if !found {
+		return
+	}
+	ecs.MustGetResource[resources.Stats](g.world).AddKill(tower.Type)
 }
 
-func (g *Game) projectileFiredEventHandler(event ecs.EventInterface) {
+func (g *Game) projectileFiredEventHandler(event events.ProjectileFiredEvent) {
 
 }
 
-func (g *Game) enemyReachedEndEventHandler(event ecs.EventInterface) {
-	// Determine the enemy damage
-	enemy, _ := g.componentAccess.GetEnemyComponent(event.Entity())
-	var enemyDamage float64
-	switch enemy.Type {
-	case "basic":
-		enemyDamage = 1
-	case "fast":
-		enemyDamage = 2
-	case "tank":
-		enemyDamage = 3
-	default:
-		panic("Unknown enemy type")
+func (g *Game) enemyReachedEndEventHandler(event events.EnemyReachedEndEvent) {
+	// The enemy may already have been killed this frame
+	enemy, found := g.componentAccess.GetEnemyComponent(event.Ent)
+	if !found {
+		return
 	}
 
-	// Get the player entity
-	playerEnt := g.world.ComponentManager.GetAllEntitiesWithComponents(
-		[]ecs.ComponentType{
-			components.Player,
-			components.Health,
-			components.Wallet,
-		},
-	)
-	if len(playerEnt) != 1 {
-		panic("Expected exactly one player entity")
-	}
+	ecs.MustGetResource[resources.Stats](g.world).Leaks++
 
-	health, _ := g.componentAccess.GetHealthComponent(playerEnt[0])
-	health.Current -= enemyDamage
+	// Take the damage off the player's health
+	player := ecs.MustGetResource[resources.Player](g.world)
+	health, _ := g.componentAccess.GetHealthComponent(player.Entity)
+	health.Current -= enemy.Damage
+	g.world.ComponentManager.MarkChanged(player.Entity, components.Health)
 	if health.Current <= 0 {
-		gameStateEnts := g.world.ComponentManager.GetAllEntitiesWithComponent(components.GameState)
-		if len(gameStateEnts) != 1 {
-			panic("Expected exactly one game state entity")
-		}
-
-		gameState, _ := g.componentAccess.GetGameStateComponent(gameStateEnts[0])
+		gameState := ecs.MustGetResource[resources.GameState](g.world)
 		gameState.GameOver = true
 
-		g.world.QueueEvent(&events.GameOverEvent{})
+		ecs.Send(g.world, events.GameOverEvent{})
 	}
 
 	// Remove the enemy entity, and all of its components
-	g.world.ComponentManager.RemoveAllComponents(event.Entity())
+	g.world.Commands().RemoveEntity(event.Ent)
+}
+
+func (g *Game) waveStartedEventHandler(event events.WaveStartedEvent) {
+	if event.Bonus > 0 {
+		g.message = fmt.Sprintf("Wave %d called early, bonus %g", event.Number, event.Bonus)
+		return
+	}
+	g.message = fmt.Sprintf("Wave %d started", event.Number)
+}
+
+func (g *Game) waveCompletedEventHandler(event events.WaveCompletedEvent) {
+	waves := ecs.MustGetResource[resources.Waves](g.world)
+	if event.Number == len(waves.Definitions) {
+		g.message = "Every wave cleared"
+		return
+	}
+	g.message = fmt.Sprintf("Wave %d cleared", event.Number)
 }
 
-func (g *Game) gameOverEventHandler(event ecs.EventInterface) {
+func (g *Game) gameOverEventHandler(event events.GameOverEvent) {
 	fmt.Println("Game Over")
 	// The player has lost
 	// End the game
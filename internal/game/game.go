@@ -1,24 +1,59 @@
 package game
 
 import (
+	"flag"
 	"log"
+	"math/rand/v2"
 	"os"
+	"strconv"
 	"time"
 
 	"ecstemplate/internal/display"
 	"ecstemplate/internal/game/components"
-	"ecstemplate/internal/game/events"
+	"ecstemplate/internal/game/data"
+	"ecstemplate/internal/game/resources"
 	"ecstemplate/internal/game/systems"
 	"ecstemplate/internal/game/ui/teaui"
 	"ecstemplate/internal/input"
 	"ecstemplate/pkg/ecs"
 )
 
+// SimulationStep is the simulated time, in seconds, each world update covers.
+// The world is always updated in steps of this size, so runs are reproducible
+// whatever the frame rate.
+const SimulationStep = 1.0 / 60
+
+// maxStepsPerUpdate caps the steps one Update can run, at four times speed and a
+// frame rate of 30
+const maxStepsPerUpdate = 8
+
+// cursorBounds is where the player can move the cursor
+var cursorBounds = input.Bounds{MinX: 0, MinY: 0, MaxX: 80, MaxY: 24}
+
 type Game struct {
 	world           *ecs.World
 	inputManager    input.InputManager
 	displayManager  display.DisplayManager
 	componentAccess *components.ComponentAccess
+	towerFactory    *systems.TowerFactorySystem
+	message         string  // Shown to the player, e.g. after saving
+	accumulator     float64 // Time waiting to be simulated, less than a step
+	seed            uint64  // Seed for the world's random numbers
+	seeded          bool    // Whether seed was set, rather than left for Initialize to pick
+	recording       *input.Replay
+	replaying       bool
+	initialized     bool
+
+	// Redraw tracking, so frames where nothing visible changed aren't drawn again
+	scene      *ecs.Query
+	paths      *ecs.Query
+	redraw     bool
+	lastDraw   ecs.Tick
+	lastScene  int // Number of scene entities at the last draw, to notice removals
+	lastPaths  int
+	lastCursor components.PositionComponent
+	lastInfo   display.GameInfo
+	lastAlpha  float64
 }
 
 func NewGame() *Game {
@@ -29,28 +64,70 @@ func NewGame() *Game {
 	// Create the component access manager
 	componentAccess := components.NewComponentAccess(world)
 
-	// Register core ECS systems
-	world.AddSystem(&systems.EnemyMovementSystem{
-		ComponentAccess: componentAccess,
-	})
-	world.AddSystem(&systems.ProjectileSystem{
-		ComponentAccess: componentAccess,
-	})
-	world.AddSystem(&systems.ProjectileCreationSystem{
-		ComponentAccess: componentAccess,
-	})
-	world.AddSystem(&systems.TowerTargetingSystem{
-		ComponentAccess: componentAccess,
-	})
-	world.AddSystem(&systems.CollisionSystem{
-		ComponentAccess: componentAccess,
-	})
-	world.AddSystem(systems.NewTowerFactorySystem(world, componentAccess))
-	world.AddSystem(systems.NewWaveSystem(componentAccess, time.Second*7))
+	// Load the towers the player can build
+	towers, err := data.Towers()
+	if err != nil {
+		logger.Fatalf("Failed to load the towers: %v", err)
+	}
 
-	inputManager := &teaui.InputManager{}
-	inputManager.Initialize()
-	inputManager.SetCursorBounds(0, 0, 80, 24)
+	// Register core ECS systems. Positions are recorded for drawing between steps,
+	// player intents and spawns are handled, then everything moves and shoots, and
+	// finally hits are resolved. Only the tower factory keeps running while the game
+	// is paused, so towers can still be placed.
+	world.AddSystemWithOptions(
+		systems.NewPositionHistorySystem(world),
+		ecs.SystemOptions{Phase: ecs.PhasePreUpdate, RunIf: resources.Running},
+	)
+	towerFactory := systems.NewTowerFactorySystem(world, towers)
+	world.AddSystemWithOptions(towerFactory, ecs.SystemOptions{Phase: ecs.PhasePreUpdate})
+	world.AddSystemWithOptions(
+		systems.NewWaveSystem(world),
+		ecs.SystemOptions{Phase: ecs.PhasePreUpdate, RunIf: resources.Running},
+	)
+	world.AddSystemWithOptions(
+		systems.NewEnemyMovementSystem(world),
+		ecs.SystemOptions{Phase: ecs.PhaseUpdate, RunIf: resources.Running},
+	)
+	world.AddSystemWithOptions(
+		systems.NewTowerTargetingSystem(world),
+		ecs.SystemOptions{
+			Phase: ecs.PhaseUpdate,
+			After: []string{"EnemyMovementSystem"},
+			RunIf: resources.Running,
+		},
+	)
+	world.AddSystemWithOptions(
+		systems.NewProjectileCreationSystem(world),
+		ecs.SystemOptions{
+			Phase: ecs.PhaseUpdate,
+			After: []string{"TowerTargetingSystem"},
+			RunIf: resources.Running,
+		},
+	)
+	world.AddSystemWithOptions(
+		systems.NewProjectileSystem(world),
+		ecs.SystemOptions{
+			Phase: ecs.PhaseUpdate,
+			After: []string{"ProjectileCreationSystem"},
+			RunIf: resources.Running,
+		},
+	)
+	world.AddSystemWithOptions(
+		systems.NewCollisionSystem(world),
+		ecs.SystemOptions{Phase: ecs.PhasePostUpdate, RunIf: resources.Running},
+	)
+
+	// Report misordered systems now rather than on the first frame
+	if err := world.BuildSchedule(); err != nil {
+		logger.Fatalf("Failed to build system schedule: %v", err)
+	}
+
+	inputManager := teaui.NewInputManager(towers)
+	if err := inputManager.Initialize(); err != nil {
+		logger.Fatalf("Failed to initialize input manager: %v", err)
+	}
+	inputManager.SetCursorBounds(cursorBounds.MinX, cursorBounds.MinY,
+		cursorBounds.MaxX, cursorBounds.MaxY)
 
 	displayManager := &teaui.DisplayManager{}
 	displayManager.Initialize(80, 24)
@@ -60,9 +137,70 @@ func NewGame() *Game {
 		inputManager:    inputManager,
 		displayManager:  displayManager,
 		componentAccess: componentAccess,
+		towerFactory:    towerFactory,
+		redraw:          true,
+	}
+}
+
+// SetSeed sets the seed for the game's random numbers. Games started with the same
+// seed and the same input play out the same. It must be called before Initialize;
+// without it a seed is picked at random.
+func (g *Game) SetSeed(seed uint64) {
+	g.seed = seed
+	g.seeded = true
+}
+
+// SeedFlag is the -seed command line flag. Every seed, 0 too, can be played again,
+// so whether it was given is kept apart from its value.
+type SeedFlag struct {
+	Seed  uint64
+	Given bool
+}
+
+// AddSeedFlag adds a -seed flag to the command line
+func AddSeedFlag() *SeedFlag {
+	seed := &SeedFlag{}
+	flag.Var(seed, "seed", "seed for the game's random numbers, picked if not given")
+	return seed
+}
+
+func (f *SeedFlag) String() string {
+	if f == nil || !f.Given {
+		return ""
+	}
+	return strconv.FormatUint(f.Seed, 10)
+}
+
+func (f *SeedFlag) Set(value string) error {
+	seed, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return err
 	}
+	f.Seed, f.Given = seed, true
+	return nil
 }
 
+// Apply sets the game's seed if the flag was given
+func (f *SeedFlag) Apply(g *Game) {
+	if f != nil && f.Given {
+		g.SetSeed(f.Seed)
+	}
+}
+
+// SetWorkers sets how many systems may run at once, see ecs.Schedule.SetWorkers.
+// Entity IDs then depend on timing, so runs with more than one worker can't be
+// replayed exactly.
+func (g *Game) SetWorkers(workers int) {
+	g.world.SetWorkers(workers)
+}
+
+// Seed returns the seed the game's random numbers were started from
+func (g *Game) Seed() uint64 {
+	return ecs.MustGetResource[ecs.Rand](g.world).Seed()
+}
+
+// Initialize starts the display and input managers and sets up the world for a new
+// run. It must be called once, before Update or Run.
 func (g *Game) Initialize(width, height int) {
 	// Initialize the display and input managers
 	if err := g.displayManager.Initialize(width, height); err != nil {
@@ -76,23 +214,50 @@ func (g *Game) Initialize(width, height int) {
 	// Register component types
 	g.registerComponentTypes()
 
+	// Index the paths by ID as they are created
+	resources.TrackPaths(g.world)
+
 	// Register event handlers
-	g.world.RegisterEventHandler(events.TowerCreated, g.towerCreatedEventHandler)
-	g.world.RegisterEventHandler(events.EnemyKilled, g.enemyKilledEventHandler)
-	g.world.RegisterEventHandler(events.ProjectileFired, g.projectileFiredEventHandler)
-	g.world.RegisterEventHandler(events.EnemyReachedEnd, g.enemyReachedEndEventHandler)
-	g.world.RegisterEventHandler(events.GameOver, g.gameOverEventHandler)
-
-	// Create the display
-	displayEnt := g.world.EntityManager.CreateEntity()
-	g.world.ComponentManager.AddComponent(
-		displayEnt,
-		components.Display,
-		&components.DisplayComponent{
-			Width:  width,
-			Height: height,
-		},
-	)
+	ecs.Subscribe(g.world, g.towerCreatedEventHandler)
+	ecs.Subscribe(g.world, g.enemyKilledEventHandler)
+	ecs.Subscribe(g.world, g.projectileFiredEventHandler)
+	ecs.Subscribe(g.world, g.enemyReachedEndEventHandler)
+	ecs.Subscribe(g.world, g.gameOverEventHandler)
+	ecs.Subscribe(g.world, g.waveStartedEventHandler)
+	ecs.Subscribe(g.world, g.waveCompletedEventHandler)
+
+	// Create the display and game state
+	ecs.InsertResource(g.world, &resources.Display{
+		Width:  width,
+		Height: height,
+	})
+	ecs.InsertResource(g.world, &resources.GameState{})
+	ecs.InsertResource(g.world, &resources.Wave{Number: 1, Phase: resources.WaveBuilding})
+	ecs.InsertResource(g.world, &resources.Stats{})
+	ecs.InsertResource(g.world, &resources.Speed{Multiplier: 1})
+	ecs.InsertResource(g.world, &resources.Interpolation{})
+
+	// Load the enemies and the waves to play
+	enemies, err := data.Enemies()
+	if err != nil {
+		g.world.Logger.Fatalf("Failed to load the enemies: %v", err)
+	}
+	waves, err := data.Waves()
+	if err == nil {
+		err = data.CheckWaves(waves, enemies)
+	}
+	if err != nil {
+		g.world.Logger.Fatalf("Failed to load the waves: %v", err)
+	}
+	ecs.InsertResource(g.world, &resources.Enemies{Archetypes: enemies})
+	ecs.InsertResource(g.world, &resources.Waves{Definitions: waves})
+
+	// Seed the world's random numbers, picking a seed if none was given
+	if !g.seeded {
+		g.seed = rand.Uint64()
+		g.seeded = true
+	}
+	ecs.InsertResource(g.world, ecs.NewRand(g.seed))
 
 	// Create the cursor
 	cursorEnt := g.world.EntityManager.CreateEntity()
@@ -109,6 +274,7 @@ func (g *Game) Initialize(width, height int) {
 			Y: 0,
 		},
 	)
+	ecs.InsertResource(g.world, &resources.Cursor{Entity: cursorEnt})
 
 	// Create the player
 	playerEnt := g.world.EntityManager.CreateEntity()
@@ -132,6 +298,7 @@ func (g *Game) Initialize(width, height int) {
 			Money: 0,
 		},
 	)
+	ecs.InsertResource(g.world, &resources.Player{Entity: playerEnt})
 
 	// Create the path
 	pathEnt := g.world.EntityManager.CreateEntity()
@@ -149,133 +316,162 @@ func (g *Game) Initialize(width, height int) {
 	)
 
 	// Create an enemy on the path
-	enemyEnt := g.world.EntityManager.CreateEntity()
-	g.world.ComponentManager.AddComponent(
-		enemyEnt,
-		components.Enemy,
-		&components.EnemyComponent{
-			Type:   "basic",
-			Speed:  1,
-			Reward: 10,
-		},
-	)
-	g.world.ComponentManager.AddComponent(
-		enemyEnt,
-		components.BoundingBox,
-		&components.BoundingBoxComponent{
-			Width:  1,
-			Height: 1,
-		},
-	)
-	g.world.ComponentManager.AddComponent(
-		enemyEnt,
-		components.Position,
-		&components.PositionComponent{
-			X: 5,
-			Y: 5,
-		},
-	)
-	g.world.ComponentManager.AddComponent(
-		enemyEnt,
-		components.Health,
-		&components.HealthComponent{
-			Current: 10,
-			Max:     10,
-		},
-	)
-	g.world.ComponentManager.AddComponent(
-		enemyEnt,
-		components.PathFollow,
-		&components.PathFollowComponent{
-			PathID:        "starting-path",
-			WaypointIndex: 0,
-		},
-	)
-	g.world.ComponentManager.AddComponent(
-		enemyEnt,
-		components.Renderable,
-		&components.RenderableComponent{
-			Symbol: "E",
-		},
-	)
+	if _, err := systems.SpawnEnemy(g.world, "basic", "starting-path", 0); err != nil {
+		g.world.Logger.Fatalf("Failed to spawn the first enemy: %v", err)
+	}
+	g.world.Commands().Apply()
+
+	// Create the starting towers, as defined in the tower data. They stand by the corner
+	// of the path, where their range covers the most of it.
+	for _, position := range []components.PositionComponent{{X: 11, Y: 8}, {X: 18, Y: 8}} {
+		_, err := g.towerFactory.CreateTower(g.world, components.BasicTower, position)
+		if err != nil {
+			g.world.Logger.Fatalf("Failed to create a starting tower: %v", err)
+		}
+	}
+	g.world.Commands().Apply()
+	g.initialized = true
+}
 
-	// Create a tower
-	towerEnt1 := g.world.EntityManager.CreateEntity()
-	g.world.ComponentManager.AddComponent(
-		towerEnt1,
-		components.Tower,
-		&components.TowerComponent{
-			Cooldown:  time.Second,
-			LastFired: time.Now(),
-			Damage:    1,
-			Range:     20,
-		},
-	)
-	g.world.ComponentManager.AddComponent(
-		towerEnt1,
-		components.Position,
-		&components.PositionComponent{
-			X: 8,
-			Y: 9,
-		},
-	)
-	g.world.ComponentManager.AddComponent(
-		towerEnt1,
-		components.Renderable,
-		&components.RenderableComponent{
-			Symbol: "T",
-		},
-	)
+func (g *Game) registerComponentTypes() {
+	// Register all component types, and the resources to save, with the world
+	components.Register(g.world)
+	resources.Register(g.world)
+}
 
-	towerEnt2 := g.world.EntityManager.CreateEntity()
-	g.world.ComponentManager.AddComponent(
-		towerEnt2,
-		components.Tower,
-		&components.TowerComponent{
-			Cooldown:  time.Second,
-			LastFired: time.Now(),
-			Damage:    1,
-			Range:     20,
-		},
-	)
-	g.world.ComponentManager.AddComponent(
-		towerEnt2,
-		components.Position,
-		&components.PositionComponent{
-			X: 20,
-			Y: 9,
-		},
-	)
-	g.world.ComponentManager.AddComponent(
-		towerEnt2,
-		components.Renderable,
-		&components.RenderableComponent{
-			Symbol: "T",
-		},
-	)
+func (g *Game) Update(deltaTime float64) {
+	if !g.initialized {
+		panic("game: Update called before Initialize")
+	}
+
+	// Gather input and update the game state in fixed steps, at the chosen speed
+	g.step(deltaTime)
 
+	// Do displaying stuff, if anything on screen changed
+	gameInfo := g.getGameInfo()
+	if !g.needsRedraw(gameInfo) {
+		return
+	}
+	g.displayManager.Clear()
+	g.displayManager.Render(g.world, g.componentAccess)
+	g.displayManager.RenderUI(gameInfo)
+	g.displayManager.Update()
 }
 
-func (g *Game) registerComponentTypes() {
-	// Register all component types with the component manager
-	for _, componentType := range components.ComponentTypes {
-		g.world.ComponentManager.RegisterComponentType(componentType)
+// step runs as many fixed simulation steps as the time since the last update calls
+// for. Input and drawing carry on while paused, the world just doesn't move on.
+//
+// Input is polled before every world update, and only then, so each set of actions
+// lands on a known tick and a replay can press them at the same point.
+func (g *Game) step(deltaTime float64) {
+	speed := ecs.MustGetResource[resources.Speed](g.world)
+	if speed.Paused {
+		// Nothing moves, but the systems that run while paused still get a turn
+		g.pollInput()
+		g.world.Update(0)
+		return
 	}
+
+	g.accumulator += speed.Scale(deltaTime)
+	steps := 0
+	for g.accumulator >= SimulationStep {
+		if steps == maxStepsPerUpdate {
+			// Too far behind to catch up, so let the simulation slow down instead
+			g.accumulator = 0
+			break
+		}
+
+		g.pollInput()
+		if speed.Paused {
+			g.world.Update(0)
+			g.accumulator = 0
+			break
+		}
+		g.world.Update(SimulationStep)
+		g.accumulator -= SimulationStep
+		steps++
+	}
+
+	interpolation := ecs.MustGetResource[resources.Interpolation](g.world)
+	interpolation.Alpha = g.accumulator / SimulationStep
 }
 
-func (g *Game) Update(deltaTime float64) {
-	// Gather and process input
+// pollInput gathers the input since the last poll and applies it to the game
+func (g *Game) pollInput() {
 	g.inputManager.Update()
+	state := g.inputManager.GetState()
+	if g.recording != nil {
+		g.recording.Record(ecs.MustGetResource[ecs.Clock](g.world).Frame, state.Pressed)
+	}
+
+	g.handleSaveActions(state)
+	g.handleSpeedActions(state)
 	g.inputManager.ProcessInputs(g.world, g.componentAccess)
+}
+
+// handleSpeedActions pauses, resumes and changes the game speed
+func (g *Game) handleSpeedActions(state input.InputState) {
+	speed := ecs.MustGetResource[resources.Speed](g.world)
+	for _, action := range state.Pressed {
+		switch action {
+		case input.ActionTogglePause:
+			speed.Paused = !speed.Paused
+		case input.ActionCycleSpeed:
+			speed.Cycle()
+		}
+	}
+}
 
-	// Update the game state
-	g.world.Update(deltaTime)
+// RequestRedraw makes the next Update draw the frame even if nothing changed,
+// e.g. after the display was resized
+func (g *Game) RequestRedraw() {
+	g.redraw = true
+}
 
-	// Do displaying stuff
-	g.displayManager.Clear()
-	g.displayManager.Render(g.world, g.componentAccess)
-	g.displayManager.RenderUI(g.getGameInfo())
-	g.displayManager.Update()
+// needsRedraw reports whether anything drawn changed since the last draw, and if
+// so records the current state as drawn
+func (g *Game) needsRedraw(gameInfo display.GameInfo) bool {
+	if g.scene == nil {
+		g.scene = g.world.ComponentManager.NewQueryWithFilter(ecs.QueryFilter{
+			With:    []ecs.ComponentType{components.Renderable, components.Position},
+			Changed: []ecs.ComponentType{components.Renderable, components.Position},
+		})
+		g.paths = g.world.ComponentManager.NewQueryWithFilter(ecs.QueryFilter{
+			With:    []ecs.ComponentType{components.Path},
+			Changed: []ecs.ComponentType{components.Path},
+		})
+	}
+
+	// The cursor is moved by input handling between updates, so compare its position
+	// instead of its change tick
+	var cursorPos components.PositionComponent
+	if cursor, found := ecs.GetResource[resources.Cursor](g.world); found {
+		if pos, found := g.componentAccess.GetPositionComponent(cursor.Entity); found {
+			cursorPos = *pos
+		}
+	}
+
+	interpolation := ecs.MustGetResource[resources.Interpolation](g.world)
+	changed := g.redraw ||
+		g.scene.Len() != g.lastScene ||
+		g.paths.Len() != g.lastPaths ||
+		len(g.scene.ChangedSince(g.lastDraw)) > 0 ||
+		len(g.paths.ChangedSince(g.lastDraw)) > 0 ||
+		cursorPos.X != g.lastCursor.X || cursorPos.Y != g.lastCursor.Y ||
+		gameInfo != g.lastInfo ||
+		interpolation.Alpha != g.lastAlpha
+	if !changed {
+		return false
+	}
+
+	g.redraw = false
+	g.lastDraw = g.world.ChangeTick()
+	g.lastScene = g.scene.Len()
+	g.lastPaths = g.paths.Len()
+	g.lastCursor = cursorPos
+	g.lastInfo = gameInfo
+	g.lastAlpha = interpolation.Alpha
+	return true
 }
 
 func (g *Game) Run() {
@@ -304,6 +500,20 @@ func (g *Game) Run() {
 	}
 }
 
+// SetDisplayManager replaces what the game is drawn with. It must be called before
+// Initialize.
+func (g *Game) SetDisplayManager(displayManager display.DisplayManager) {
+	g.displayManager = displayManager
+}
+
+// SetInputManager replaces where the game's input comes from. It must be called
+// before Initialize.
+func (g *Game) SetInputManager(inputManager input.InputManager) {
+	inputManager.SetCursorBounds(cursorBounds.MinX, cursorBounds.MinY,
+		cursorBounds.MaxX, cursorBounds.MaxY)
+	g.inputManager = inputManager
+}
+
 func (g *Game) GetDisplayManager() display.DisplayManager {
 	return g.displayManager
 }
@@ -314,16 +524,26 @@ func (g *Game) GetInputManager() input.InputManager {
 
 func (g *Game) getGameInfo() display.GameInfo {
 	// Get the player health and money
-	playerEnt := g.world.ComponentManager.GetAllEntitiesWithComponent(components.Player)[0]
-	health, _ := g.componentAccess.GetHealthComponent(playerEnt)
-	wallet, _ := g.componentAccess.GetWalletComponent(playerEnt)
+	player := ecs.MustGetResource[resources.Player](g.world)
+	health, _ := g.componentAccess.GetHealthComponent(player.Entity)
+	wallet, _ := g.componentAccess.GetWalletComponent(player.Entity)
+	gameState := ecs.MustGetResource[resources.GameState](g.world)
+	speed := ecs.MustGetResource[resources.Speed](g.world)
+	wave := ecs.MustGetResource[resources.Wave](g.world)
+	waves := ecs.MustGetResource[resources.Waves](g.world)
+	now := ecs.MustGetResource[ecs.Clock](g.world).Elapsed
 
 	return display.GameInfo{
-		PlayerHealth: health.Current,
-		PlayerMoney:  wallet.Money,
-		CurrentWave:  1,
-		WaveProgress: 0.0,
-		GameOver:     false,
-		Message:      "",
+		PlayerHealth:  health.Current,
+		PlayerMoney:   wallet.Money,
+		CurrentWave:   wave.Number,
+		WaveProgress:  wave.Progress(),
+		WaveCountdown: wave.Countdown(waves, now),
+		EarlyBonus:    wave.EarlyBonus(waves, now),
+		GameOver:      gameState.GameOver,
+		Message:       g.message,
+		Speed:         speed.Multiplier,
+		Seed:          g.Seed(),
+		Paused:        speed.Paused,
 	}
 }
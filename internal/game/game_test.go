@@ -0,0 +1,476 @@
+package game
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"ecstemplate/internal/game/components"
+	"ecstemplate/internal/game/data"
+	"ecstemplate/internal/game/resources"
+	"ecstemplate/internal/game/ui/headless"
+	"ecstemplate/internal/game/ui/teaui"
+	"ecstemplate/internal/input"
+	"ecstemplate/pkg/ecs"
+)
+
+func snapshotJSON(t *testing.T, g *Game) string {
+	var out bytes.Buffer
+	if err := g.world.SaveSnapshot(&out, ecs.SnapshotJSON); err != nil {
+		t.Fatalf("Expected the world to save, got %v", err)
+	}
+	return out.String()
+}
+
+func TestFixedTimestepIgnoresFrameJitter(t *testing.T) {
+	steady := NewGame()
+	steady.SetSeed(1)
+	steady.Initialize(80, 24)
+	jittery := NewGame()
+	jittery.SetSeed(1)
+	jittery.Initialize(80, 24)
+
+	// Ten simulated seconds, at a steady 60 FPS and at an uneven frame rate
+	for range 600 {
+		steady.Update(1.0 / 60)
+	}
+	for range 300 {
+		jittery.Update(1.0 / 120)
+		jittery.Update(1.0 / 40)
+	}
+
+	steadyFrames := ecs.MustGetResource[ecs.Clock](steady.world).Frame
+	jitteryFrames := ecs.MustGetResource[ecs.Clock](jittery.world).Frame
+	if steadyFrames != 600 || jitteryFrames != 600 {
+		t.Fatalf("Expected 600 simulation steps each, got %d and %d", steadyFrames, jitteryFrames)
+	}
+	if snapshotJSON(t, steady) != snapshotJSON(t, jittery) {
+		t.Errorf("Expected both games to reach the same state")
+	}
+}
+
+// steppingInputManager presses the same action every time it's polled, and counts the polls
+type steppingInputManager struct {
+	action input.Action
+	polls  int
+	state  input.InputState
+	bounds input.Bounds
+}
+
+func (im *steppingInputManager) Initialize() error {
+	im.state = input.NewInputState()
+	return nil
+}
+
+func (im *steppingInputManager) Update() {
+	im.state.Reset()
+	im.state.Press(im.action)
+	im.polls++
+}
+
+func (im *steppingInputManager) GetState() input.InputState {
+	return im.state
+}
+
+func (im *steppingInputManager) ProcessInputs(
+	world *ecs.World,
+	componentAccess *components.ComponentAccess,
+) {
+	input.ApplyActions(world, componentAccess, &im.state, im.bounds)
+}
+
+func (im *steppingInputManager) SetCursorBounds(minX, minY, maxX, maxY int) {
+	im.bounds = input.Bounds{MinX: minX, MinY: minY, MaxX: maxX, MaxY: maxY}
+}
+
+func (im *steppingInputManager) Shutdown() {}
+
+// countingDisplayManager counts the frames drawn
+type countingDisplayManager struct {
+	headless.DisplayManager
+	frames int
+}
+
+func (dm *countingDisplayManager) Update() {
+	dm.frames++
+}
+
+func TestPausingStopsTheSimulationButNotInput(t *testing.T) {
+	g := NewGame()
+	displayManager := &countingDisplayManager{}
+	inputManager := &steppingInputManager{action: input.ActionMoveRight}
+	g.SetDisplayManager(displayManager)
+	g.SetInputManager(inputManager)
+	g.SetSeed(1)
+	g.Initialize(80, 24)
+
+	// Get some enemies on the path, so there's something that would move
+	for range 900 {
+		g.Update(SimulationStep)
+	}
+	ecs.MustGetResource[resources.Speed](g.world).Paused = true
+	inputManager.action = input.ActionMoveLeft // The cursor is against the right edge by now
+	clock := ecs.MustGetResource[ecs.Clock](g.world)
+	elapsed, before := clock.Elapsed, capturePlayState(g)
+	polls, frames := inputManager.polls, displayManager.frames
+	cursor := ecs.MustGetResource[resources.Cursor](g.world)
+	cursorPos, _ := ecs.NewStore[components.PositionComponent](g.world).Get(cursor.Entity)
+	cursorX := cursorPos.X
+
+	for range 5 {
+		g.Update(SimulationStep)
+	}
+	if clock.Elapsed != elapsed {
+		t.Errorf("Expected no simulation time to pass while paused, went from %v to %v",
+			elapsed, clock.Elapsed)
+	}
+	if after := capturePlayState(g); !reflect.DeepEqual(after, before) {
+		t.Errorf("Expected nothing to move while paused\nbefore: %+v\nafter:  %+v", before, after)
+	}
+	if n := inputManager.polls - polls; n != 5 {
+		t.Errorf("Expected input to be polled on each of the 5 paused updates, got %d", n)
+	}
+	if cursorPos.X != cursorX-5 {
+		t.Errorf("Expected the cursor to move from %g to %g while paused, got %g",
+			cursorX, cursorX-5, cursorPos.X)
+	}
+	if n := displayManager.frames - frames; n != 5 {
+		t.Errorf("Expected each cursor move to be drawn while paused, got %d frames", n)
+	}
+}
+
+func TestFasterSpeedsRunMoreSteps(t *testing.T) {
+	frames := map[float64]uint64{}
+	for _, multiplier := range resources.Speeds {
+		g := NewGame()
+		g.SetSeed(1)
+		g.Initialize(80, 24)
+		ecs.MustGetResource[resources.Speed](g.world).Multiplier = multiplier
+
+		// The same wall clock time at each speed
+		for range 60 {
+			g.Update(1.0 / 30)
+		}
+		frames[multiplier] = ecs.MustGetResource[ecs.Clock](g.world).Frame
+	}
+
+	if frames[1] != 120 {
+		t.Fatalf("Expected 120 steps at 1x, got %d", frames[1])
+	}
+	if frames[2] != 2*frames[1] || frames[4] != 4*frames[1] {
+		t.Errorf("Expected 2x and 4x to run 2 and 4 times the steps of 1x, got %v", frames)
+	}
+}
+
+func TestReplayReproducesGame(t *testing.T) {
+	// Play a game with a steady frame rate, pausing, moving, building and speeding up
+	recorded := NewGame()
+	recorded.SetSeed(7)
+	recorded.Initialize(80, 24)
+	recorded.StartRecording()
+
+	keys := map[int][]string{
+		30:   {"d", "d", "s"},
+		100:  {"p"},
+		105:  {"s", "1"},
+		106:  {"enter"},
+		120:  {"p"},
+		1500: {"2", "a", "enter"},
+		2000: {"f"},
+		2500: {"3", "w", "w", "enter", "f"},
+	}
+	keyboard := recorded.GetInputManager().(*teaui.InputManager)
+	for frame := range 3600 {
+		for _, key := range keys[frame] {
+			keyboard.QueueKey(key)
+		}
+		recorded.Update(1.0 / 60)
+	}
+
+	// Three tower templates, the two starting towers, and the ones the player built
+	towers := recorded.world.ComponentManager.GetAllEntitiesWithComponent(components.Tower)
+	if len(towers) <= 5 {
+		t.Fatalf("Expected the player to have built towers, got %d towers", len(towers))
+	}
+
+	var file bytes.Buffer
+	if err := recorded.Recording().Write(&file); err != nil {
+		t.Fatalf("Expected the replay to be written, got %v", err)
+	}
+	replay, err := input.ReadReplay(&file)
+	if err != nil {
+		t.Fatalf("Expected the replay to be read, got %v", err)
+	}
+
+	// Play it back at an uneven frame rate, up to the same tick
+	played := NewGame()
+	played.PlayReplay(replay)
+	played.Initialize(replay.Width, replay.Height)
+
+	target := ecs.MustGetResource[ecs.Clock](recorded.world).Frame
+	clock := ecs.MustGetResource[ecs.Clock](played.world)
+	for frame := 0; clock.Frame < target; frame++ {
+		if target-clock.Frame < 8 {
+			// Small enough to run at most one step, even at four times speed
+			played.Update(1.0 / 240)
+		} else if frame%2 == 0 {
+			played.Update(1.0 / 120)
+		} else {
+			played.Update(1.0 / 40)
+		}
+	}
+
+	if !played.GetInputManager().(*input.PlaybackInputManager).Done() {
+		t.Errorf("Expected every recorded action to have been played")
+	}
+	if clock.Frame != target {
+		t.Fatalf("Expected playback to stop at tick %d, got %d", target, clock.Frame)
+	}
+	if snapshotJSON(t, recorded) != snapshotJSON(t, played) {
+		t.Errorf("Expected the replay to reach the same state as the recorded game")
+	}
+}
+
+func TestSimulateHeadless(t *testing.T) {
+	g := NewGame()
+	g.SetDisplayManager(&headless.DisplayManager{})
+	g.SetInputManager(&headless.InputManager{})
+	g.SetSeed(1)
+	g.Initialize(80, 24)
+
+	summary := g.Simulate(3, 60*60*10)
+	if summary.GameOver || summary.WavesCleared != 3 {
+		t.Fatalf("Expected three waves cleared, got %+v", summary)
+	}
+
+	// Every enemy of the three waves, and the one the game starts with, was either
+	// killed by one of the starting towers or got through
+	enemies := 1
+	for _, wave := range ecs.MustGetResource[resources.Waves](g.world).Definitions[:3] {
+		for _, group := range wave.Groups {
+			enemies += group.Count
+		}
+	}
+	if kills := summary.Kills[components.BasicTower]; kills+summary.Leaks != enemies {
+		t.Errorf("Expected %d enemies killed or leaked, got %d kills and %d leaks",
+			enemies, kills, summary.Leaks)
+	}
+}
+
+func TestSimulateWithWorkers(t *testing.T) {
+	g := NewGame()
+	g.SetDisplayManager(&headless.DisplayManager{})
+	g.SetInputManager(&headless.InputManager{})
+	g.SetSeed(1)
+	g.SetWorkers(4)
+	g.Initialize(80, 24)
+
+	// Some of the game's systems have to share a batch, or nothing runs in parallel
+	batches, err := g.world.Schedule().Batches()
+	if err != nil {
+		t.Fatalf("Expected the schedule to build, got %v", err)
+	}
+	if !slices.ContainsFunc(batches, func(batch []string) bool { return len(batch) > 1 }) {
+		t.Fatalf("Expected systems to run in parallel, got batches %v", batches)
+	}
+
+	// Run with -race, this checks the systems' declared access is honest
+	summary := g.Simulate(3, 60*60*10)
+	if summary.GameOver || summary.WavesCleared != 3 {
+		t.Errorf("Expected three waves cleared, got %+v", summary)
+	}
+}
+
+func TestRenderedChangesAreMarked(t *testing.T) {
+	g := NewGame()
+	g.SetDisplayManager(&headless.DisplayManager{})
+	g.SetInputManager(&headless.InputManager{})
+	g.SetSeed(1)
+	g.Initialize(80, 24)
+
+	// Redraws are skipped unless something drawn is marked as changed, so any system
+	// writing drawn components without marking them leaves the screen stale
+	type drawn struct {
+		position   components.PositionComponent
+		renderable components.RenderableComponent
+	}
+	scene := g.world.ComponentManager.NewQuery(
+		[]ecs.ComponentType{components.Renderable, components.Position},
+	)
+	for step := range 60 * 60 {
+		before := make(map[ecs.Entity]drawn)
+		for _, entity := range scene.Entities() {
+			pos, _ := g.componentAccess.GetPositionComponent(entity)
+			rend, _ := g.componentAccess.GetRenderableComponent(entity)
+			before[entity] = drawn{*pos, *rend}
+		}
+		tick := g.world.ChangeTick()
+		g.Update(SimulationStep)
+
+		for _, entity := range scene.Entities() {
+			was, found := before[entity]
+			if !found {
+				continue
+			}
+			pos, _ := g.componentAccess.GetPositionComponent(entity)
+			rend, _ := g.componentAccess.GetRenderableComponent(entity)
+			cm := g.world.ComponentManager
+			if *pos != was.position && !cm.ChangedSince(entity, components.Position, tick) {
+				t.Fatalf("Step %d: entity %d moved without being marked", step, entity)
+			}
+			if *rend != was.renderable &&
+				!cm.ChangedSince(entity, components.Renderable, tick) {
+				t.Fatalf("Step %d: entity %d was redrawn without being marked", step, entity)
+			}
+		}
+	}
+}
+
+func TestSeedZeroIsKept(t *testing.T) {
+	// 0 is a seed like any other, rather than a request to pick one
+	first := NewGame()
+	first.SetSeed(0)
+	first.Initialize(80, 24)
+	second := NewGame()
+	second.SetSeed(0)
+	second.Initialize(80, 24)
+
+	if first.Seed() != 0 || second.Seed() != 0 {
+		t.Fatalf("Expected both games to keep seed 0, got %d and %d", first.Seed(), second.Seed())
+	}
+	for range 600 {
+		first.Update(SimulationStep)
+		second.Update(SimulationStep)
+	}
+	if snapshotJSON(t, first) != snapshotJSON(t, second) {
+		t.Errorf("Expected both games to reach the same state")
+	}
+}
+
+func TestSeedFlagOnlyAppliesWhenGiven(t *testing.T) {
+	parse := func(args ...string) *SeedFlag {
+		seed := &SeedFlag{}
+		flags := flag.NewFlagSet("test", flag.ContinueOnError)
+		flags.Var(seed, "seed", "")
+		if err := flags.Parse(args); err != nil {
+			t.Fatalf("Expected %v to parse, got %v", args, err)
+		}
+		return seed
+	}
+
+	given := parse("-seed", "0")
+	if !given.Given || given.Seed != 0 {
+		t.Fatalf("Expected -seed 0 to be given as 0, got %+v", *given)
+	}
+	g := NewGame()
+	given.Apply(g)
+	g.Initialize(80, 24)
+	if g.Seed() != 0 {
+		t.Errorf("Expected the game to keep seed 0, got %d", g.Seed())
+	}
+
+	if missing := parse(); missing.Given {
+		t.Errorf("Expected no seed to be given, got %+v", *missing)
+	}
+	if err := (&SeedFlag{}).Set("lots"); err == nil {
+		t.Error("Expected a seed that isn't a number to fail")
+	}
+}
+
+func TestLoadingStopsRecording(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	g := NewGame()
+	g.SetDisplayManager(&headless.DisplayManager{})
+	g.SetInputManager(&headless.InputManager{})
+	g.SetSeed(1)
+	g.Initialize(80, 24)
+	g.StartRecording()
+
+	path := filepath.Join(t.TempDir(), "game.replay")
+	if err := g.SaveRecording(path); err != nil {
+		t.Fatalf("Expected the replay to save, got %v", err)
+	}
+	if err := g.Save(1); err != nil {
+		t.Fatalf("Expected the game to save, got %v", err)
+	}
+
+	// A replay can't reproduce a jump to a save, so there's nothing to write after one,
+	// and the replay already written is left alone
+	state := input.NewInputState()
+	state.SaveSlot = 1
+	state.Press(input.ActionLoad)
+	g.handleSaveActions(state)
+	if err := g.SaveRecording(path); !errors.Is(err, ErrNotRecording) {
+		t.Fatalf("Expected saving the replay after a load to fail, got %v", err)
+	}
+	if _, err := LoadReplay(path); err != nil {
+		t.Errorf("Expected the earlier replay to still load, got %v", err)
+	}
+}
+
+func TestUpdateBeforeInitializePanics(t *testing.T) {
+	g := NewGame()
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(fmt.Sprint(r), "before Initialize") {
+			t.Errorf("Expected updating before Initialize to panic saying so, got %v", r)
+		}
+	}()
+	g.Update(SimulationStep)
+}
+
+func TestTinyDisplayDoesNotPanic(t *testing.T) {
+	// The status lines are clipped to whatever fits, down to no screen at all
+	for _, size := range [][2]int{{20, 4}, {1, 1}, {0, 0}} {
+		g := NewGame()
+		g.SetSeed(1)
+		g.Initialize(size[0], size[1])
+		for range 60 {
+			g.Update(SimulationStep)
+		}
+	}
+}
+
+func TestTowerHotkeyClashingWithAKeyFails(t *testing.T) {
+	towers := []resources.TowerDefinition{{Type: "sniper", Hotkey: "w"}}
+	if err := teaui.NewInputManager(towers).Initialize(); err == nil {
+		t.Errorf("Expected a tower hotkey on the move up key to fail")
+	}
+}
+
+func TestStartingTowersUseTheirDefinition(t *testing.T) {
+	g := NewGame()
+	g.SetSeed(1)
+	g.Initialize(80, 24)
+
+	towers, err := data.Towers()
+	if err != nil {
+		t.Fatalf("Expected the towers to load, got %v", err)
+	}
+	basic := towers[slices.IndexFunc(towers, func(tower resources.TowerDefinition) bool {
+		return tower.Type == components.BasicTower
+	})]
+
+	// The placed towers are the ones with a Renderable, unlike the templates
+	placed := g.world.ComponentManager.GetAllEntitiesWithComponents(
+		[]ecs.ComponentType{components.Tower, components.Renderable},
+	)
+	if len(placed) != 2 {
+		t.Fatalf("Expected two starting towers, got %d", len(placed))
+	}
+	specs := ecs.NewStore[components.ProjectileSpecComponent](g.world)
+	for _, towerEnt := range placed {
+		tower, _ := g.componentAccess.GetTowerComponent(towerEnt)
+		spec, _ := specs.Get(towerEnt)
+		if tower.Range != basic.Range || tower.Cooldown != basic.Cooldown ||
+			*spec != basic.Projectile {
+			t.Errorf("Expected starting tower %d to match the basic tower, got %+v and %+v",
+				towerEnt, tower, spec)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package game
+
+import (
+	"errors"
+	"os"
+
+	"ecstemplate/internal/game/resources"
+	"ecstemplate/internal/input"
+	"ecstemplate/pkg/ecs"
+)
+
+// ErrNotRecording is returned by SaveRecording when there's no replay to save, because
+// recording was never started or stopped when a save was loaded
+var ErrNotRecording = errors.New("no replay is being recorded")
+
+// StartRecording records every action pressed from now on into a replay.
+// It must be called after Initialize.
+func (g *Game) StartRecording() {
+	display := ecs.MustGetResource[resources.Display](g.world)
+	g.recording = input.NewReplay(g.Seed(), display.Width, display.Height)
+}
+
+// Recording returns the replay being recorded, or nil if there isn't one
+func (g *Game) Recording() *input.Replay {
+	return g.recording
+}
+
+// SaveRecording writes the replay being recorded to a file. Without one, the file
+// is left alone and ErrNotRecording is returned.
+func (g *Game) SaveRecording(path string) error {
+	if g.recording == nil {
+		return ErrNotRecording
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := g.recording.Write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// PlayReplay makes the game press the replay's actions instead of reading input.
+// It must be called before Initialize, which should be given the replay's Width
+// and Height.
+func (g *Game) PlayReplay(replay *input.Replay) {
+	g.SetInputManager(input.NewPlaybackInputManager(g.world, replay))
+	g.replaying = true
+	g.SetSeed(replay.Seed)
+}
+
+// LoadReplay reads a replay file written by SaveRecording
+func LoadReplay(path string) (*input.Replay, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return input.ReadReplay(file)
+}
@@ -0,0 +1,238 @@
+package resources
+
+import (
+	"math"
+	"time"
+
+	"ecstemplate/internal/game/components"
+	"ecstemplate/pkg/ecs"
+)
+
+// Player points at the player entity, which carries the Player, Health and Wallet components
+type Player struct {
+	Entity ecs.Entity
+}
+
+// Cursor points at the cursor entity, which carries the Cursor and Position components
+type Cursor struct {
+	Entity ecs.Entity
+}
+
+// Display is the size of the play area
+type Display struct {
+	Width, Height int
+}
+
+// GameState tracks the overall state of the run
+type GameState struct {
+	GameOver bool
+}
+
+// TowerDefinition is a type of tower the player can build, see the data package for
+// where they come from
+type TowerDefinition struct {
+	Type       components.TowerType
+	Name       string
+	Hotkey     string // Key that picks the tower to build
+	Cost       float64
+	Cooldown   time.Duration // Time between shots
+	Range      float64
+	Projectile components.ProjectileSpecComponent // What it fires, copied onto each tower
+	Symbol     string
+	Color      string                 // Hex colour to draw it in, e.g. "#FF0000"
+	Upgrades   []components.TowerType // Types it can be upgraded to
+}
+
+// EnemyArchetype is the numbers and looks of a type of enemy, see the data package
+// for where they come from
+type EnemyArchetype struct {
+	Type          string
+	Speed         float64 // Tiles a second
+	Health        float64
+	Reward        float64 // Money for killing it
+	Damage        float64 // Health the player loses if it gets through
+	Width, Height float64 // Size of its bounding box
+	Symbol        string
+	Color         string   // Hex colour to draw it in, e.g. "#FF0000"
+	Abilities     []string // Special abilities, copied onto its EnemyComponent
+}
+
+// Enemies are the enemy archetypes, by type
+type Enemies struct {
+	Archetypes map[string]EnemyArchetype
+}
+
+// Get returns the archetype of the enemy type
+func (e *Enemies) Get(enemyType string) (EnemyArchetype, bool) {
+	archetype, found := e.Archetypes[enemyType]
+	return archetype, found
+}
+
+// WaveDefinition is one wave of enemies, see the data package for where they come from
+type WaveDefinition struct {
+	Delay  time.Duration // Time to build in before the wave starts
+	Groups []SpawnGroup  // Spawned one after the other
+}
+
+// SpawnGroup is a number of enemies of one type, spawned one at a time on a path
+type SpawnGroup struct {
+	Enemy   string        // Enemy type
+	Count   int           // Number of enemies to spawn
+	Spacing time.Duration // Time between spawns
+	Path    string        // ID of the path the enemies follow
+	Delay   time.Duration // Time before the first spawn, after the group before
+}
+
+// Waves are the waves of the run, in order
+type Waves struct {
+	Definitions []WaveDefinition
+}
+
+// WavePhase is where a wave is up to. Each wave is built for, spawned and cleared,
+// then the next wave's build phase starts, until every wave is complete.
+type WavePhase string
+
+const (
+	WaveBuilding WavePhase = "building" // Waiting for the wave to start
+	WaveSpawning WavePhase = "spawning" // Spawning the wave's groups
+	WaveClearing WavePhase = "clearing" // Waiting for the wave's enemies to be gone
+	WaveComplete WavePhase = "complete" // Every wave has been cleared
+)
+
+// Wave is the state of the current wave
+type Wave struct {
+	Number       int // Current wave, counting from 1
+	Phase        WavePhase
+	PhaseStart   time.Duration // Simulation time, see ecs.Clock
+	Completed    int           // Waves cleared so far
+	Group        int           // Index of the group being spawned
+	GroupSpawned int           // Enemies spawned from the group so far
+	LastSpawn    time.Duration // Simulation time
+	Spawned      int           // Enemies spawned in the wave so far
+	Total        int           // Enemies in the wave
+	Remaining    int           // Enemies of the wave still on the map
+}
+
+// EarlyCallBonus is the money paid for each second of build time skipped by
+// calling a wave early
+const EarlyCallBonus = 2
+
+// Countdown returns the build time left before the wave starts, or 0 if it isn't
+// being built for
+func (w *Wave) Countdown(waves *Waves, now time.Duration) time.Duration {
+	if w.Phase != WaveBuilding || w.Number > len(waves.Definitions) {
+		return 0
+	}
+	delay := waves.Definitions[w.Number-1].Delay
+	return max(delay-(now-w.PhaseStart), 0)
+}
+
+// EarlyBonus returns the money paid for calling the wave now, in whole units
+func (w *Wave) EarlyBonus(waves *Waves, now time.Duration) float64 {
+	return math.Floor(w.Countdown(waves, now).Seconds() * EarlyCallBonus)
+}
+
+// Progress returns how much of the wave has been killed or got through, from 0 to 1
+func (w *Wave) Progress() float64 {
+	switch {
+	case w.Phase == WaveComplete:
+		return 1
+	case w.Phase == WaveBuilding || w.Total == 0:
+		return 0
+	}
+	return float64(w.Spawned-w.Remaining) / float64(w.Total)
+}
+
+// Stats counts how the run is going, for the player and for tuning the game
+type Stats struct {
+	Leaks int                          // Enemies that reached the end of their path
+	Kills map[components.TowerType]int // Enemies killed by each type of tower
+}
+
+// AddKill counts an enemy killed by a tower of the given type
+func (s *Stats) AddKill(towerType components.TowerType) {
+	if s.Kills == nil {
+		s.Kills = make(map[components.TowerType]int)
+	}
+	s.Kills[towerType]++
+}
+
+// Speeds are the game speed multipliers the player can pick between
+var Speeds = []float64{1, 2, 4}
+
+// Speed controls how fast the simulation runs relative to real time
+type Speed struct {
+	Paused     bool
+	Multiplier float64 // One of Speeds
+}
+
+// Scale returns how much simulation time passes in deltaTime seconds of real time
+func (s *Speed) Scale(deltaTime float64) float64 {
+	if s.Paused {
+		return 0
+	}
+	return deltaTime * s.Multiplier
+}
+
+// Cycle moves on to the next speed, wrapping back to the slowest
+func (s *Speed) Cycle() {
+	next := 0
+	for i, speed := range Speeds {
+		if speed == s.Multiplier {
+			next = (i + 1) % len(Speeds)
+		}
+	}
+	s.Multiplier = Speeds[next]
+}
+
+// Interpolation is how far the game has got towards the next simulation step, from
+// 0 to 1. Renderers draw moving entities that far between their PreviousPosition
+// and Position, so movement looks smooth whatever the frame rate.
+type Interpolation struct {
+	Alpha float64
+}
+
+// Running is a run condition for systems that should stop while the game is paused
+func Running(world *ecs.World) bool {
+	speed, found := ecs.GetResource[Speed](world)
+	return !found || !speed.Paused
+}
+
+// Register includes the resources that aren't derived from components in world
+// snapshots. Paths is rebuilt from the path components when a snapshot is loaded.
+func Register(world *ecs.World) {
+	ecs.RegisterResource[Player](world, "player")
+	ecs.RegisterResource[Cursor](world, "cursor")
+	ecs.RegisterResource[Display](world, "display")
+	ecs.RegisterResource[GameState](world, "game_state")
+	ecs.RegisterResource[Wave](world, "wave")
+	ecs.RegisterResource[Stats](world, "stats")
+}
+
+// Paths looks up path entities by their PathComponent ID.
+// It is kept in sync by component observers, see TrackPaths.
+type Paths struct {
+	byID map[string]ecs.Entity
+}
+
+// Get returns the entity of the path with the given ID
+func (p *Paths) Get(id string) (ecs.Entity, bool) {
+	entity, found := p.byID[id]
+	return entity, found
+}
+
+// TrackPaths inserts the Paths resource and keeps it up to date as path
+// components are added and removed
+func TrackPaths(world *ecs.World) {
+	paths := &Paths{byID: make(map[string]ecs.Entity)}
+	ecs.InsertResource(world, paths)
+
+	ecs.OnAdd(world, func(entity ecs.Entity, path *components.PathComponent) {
+		paths.byID[path.ID] = entity
+	})
+	ecs.OnRemove(world, func(entity ecs.Entity, path *components.PathComponent) {
+		if paths.byID[path.ID] == entity {
+			delete(paths.byID, path.ID)
+		}
+	})
+}
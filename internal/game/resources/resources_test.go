@@ -0,0 +1,31 @@
+package resources
+
+import "testing"
+
+func TestSpeedCycleWraps(t *testing.T) {
+	speed := Speed{Multiplier: 1}
+	for _, want := range []float64{2, 4, 1, 2} {
+		speed.Cycle()
+		if speed.Multiplier != want {
+			t.Fatalf("Expected the speed to cycle to %g, got %g", want, speed.Multiplier)
+		}
+	}
+
+	// A speed that isn't one of Speeds, from an old save say, starts again from the slowest
+	speed.Multiplier = 3
+	speed.Cycle()
+	if speed.Multiplier != Speeds[0] {
+		t.Errorf("Expected an unknown speed to cycle to %g, got %g", Speeds[0], speed.Multiplier)
+	}
+}
+
+func TestSpeedScale(t *testing.T) {
+	speed := Speed{Multiplier: 2}
+	if scaled := speed.Scale(0.5); scaled != 1 {
+		t.Errorf("Expected half a second at 2x to be 1s, got %g", scaled)
+	}
+	speed.Paused = true
+	if scaled := speed.Scale(0.5); scaled != 0 {
+		t.Errorf("Expected no time to pass while paused, got %g", scaled)
+	}
+}
@@ -0,0 +1,113 @@
+package game
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"ecstemplate/internal/input"
+	"ecstemplate/pkg/ecs"
+)
+
+// SaveDir returns the directory save slots are kept in, under the user's config directory
+func SaveDir() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "ecstemplate", "saves"), nil
+}
+
+func slotPath(slot int) (string, error) {
+	if slot < 1 || slot > input.SaveSlots {
+		return "", fmt.Errorf("save slot %d does not exist", slot)
+	}
+	dir, err := SaveDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, fmt.Sprintf("slot%d.sav", slot)), nil
+}
+
+// Save writes the run in progress to the save slot, replacing what was there.
+// It must be called between updates.
+func (g *Game) Save(slot int) error {
+	path, err := slotPath(slot)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first, so a failed save doesn't lose the old one
+	file, err := os.CreateTemp(filepath.Dir(path), "save-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if err := g.world.SaveSnapshot(file, ecs.SnapshotBinary); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
+
+// Load replaces the run in progress with the one in the save slot.
+// It must be called between updates.
+func (g *Game) Load(slot int) error {
+	path, err := slotPath(slot)
+	if err != nil {
+		return err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := g.world.LoadSnapshot(file, ecs.SnapshotBinary); err != nil {
+		return err
+	}
+	g.RequestRedraw()
+	return nil
+}
+
+// handleSaveActions saves, loads and picks save slots for the player.
+// A replay doesn't touch the save slots when it's played back.
+func (g *Game) handleSaveActions(state input.InputState) {
+	if g.replaying {
+		return
+	}
+
+	switch {
+	case state.Actions[input.ActionSave]:
+		if err := g.Save(state.SaveSlot); err != nil {
+			g.world.Logger.Printf("Failed to save to slot %d: %v", state.SaveSlot, err)
+			g.message = fmt.Sprintf("Save to slot %d failed", state.SaveSlot)
+			return
+		}
+		g.message = fmt.Sprintf("Saved to slot %d", state.SaveSlot)
+
+	case state.Actions[input.ActionLoad]:
+		if err := g.Load(state.SaveSlot); err != nil {
+			g.world.Logger.Printf("Failed to load slot %d: %v", state.SaveSlot, err)
+			g.message = fmt.Sprintf("Load from slot %d failed", state.SaveSlot)
+			return
+		}
+		g.message = fmt.Sprintf("Loaded slot %d", state.SaveSlot)
+
+		// The replay can't reproduce a game that jumped to a save
+		if g.recording != nil {
+			g.world.Logger.Printf("Stopped recording the replay, since a save was loaded")
+			g.recording = nil
+		}
+
+	case state.Actions[input.ActionNextSlot]:
+		g.message = fmt.Sprintf("Save slot %d", state.SaveSlot)
+	}
+}
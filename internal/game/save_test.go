@@ -0,0 +1,143 @@
+package game
+
+import (
+	"errors"
+	"io/fs"
+	"reflect"
+	"testing"
+
+	"ecstemplate/internal/game/components"
+	"ecstemplate/internal/game/resources"
+	"ecstemplate/internal/game/ui/headless"
+	"ecstemplate/pkg/ecs"
+)
+
+// playState is what a save has to bring back for the run to carry on the same
+type playState struct {
+	Money       float64
+	Health      map[ecs.Entity]float64
+	Followers   map[ecs.Entity]follower
+	Projectiles map[ecs.Entity]components.PositionComponent
+	Wave        resources.Wave
+}
+
+type follower struct {
+	WaypointIndex int
+	Position      components.PositionComponent
+}
+
+func capturePlayState(g *Game) playState {
+	state := playState{
+		Money:       money(g),
+		Health:      map[ecs.Entity]float64{},
+		Followers:   map[ecs.Entity]follower{},
+		Projectiles: map[ecs.Entity]components.PositionComponent{},
+		Wave:        *ecs.MustGetResource[resources.Wave](g.world),
+	}
+	positions := ecs.NewStore[components.PositionComponent](g.world)
+	ecs.NewStore[components.HealthComponent](g.world).Each(
+		func(entity ecs.Entity, health *components.HealthComponent) {
+			state.Health[entity] = health.Current
+		},
+	)
+	ecs.NewStore[components.PathFollowComponent](g.world).Each(
+		func(entity ecs.Entity, pathFollow *components.PathFollowComponent) {
+			position, _ := positions.Get(entity)
+			state.Followers[entity] = follower{pathFollow.WaypointIndex, *position}
+		},
+	)
+	ecs.NewStore[components.ProjectileComponent](g.world).Each(
+		func(entity ecs.Entity, _ *components.ProjectileComponent) {
+			position, _ := positions.Get(entity)
+			state.Projectiles[entity] = *position
+		},
+	)
+	return state
+}
+
+func TestLoadingCarriesOnTheSameRun(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	g := NewGame()
+	g.SetDisplayManager(&headless.DisplayManager{})
+	g.SetInputManager(&headless.InputManager{})
+	g.SetSeed(1)
+	g.Initialize(80, 24)
+
+	// Play into the first wave, until there are enemies on the path and a shot in flight
+	for range 3000 {
+		g.Update(SimulationStep)
+		if state := capturePlayState(g); len(state.Followers) > 1 && len(state.Projectiles) > 0 {
+			break
+		}
+	}
+	saved := capturePlayState(g)
+	if len(saved.Followers) < 2 || len(saved.Projectiles) == 0 {
+		t.Fatalf("Expected enemies and projectiles to save, got %+v", saved)
+	}
+	savedSnapshot := snapshotJSON(t, g)
+	if err := g.Save(1); err != nil {
+		t.Fatalf("Expected the game to save, got %v", err)
+	}
+
+	const ticks = 600
+	for range ticks {
+		g.Update(SimulationStep)
+	}
+	played := capturePlayState(g)
+	playedSnapshot := snapshotJSON(t, g)
+	if reflect.DeepEqual(played, saved) {
+		t.Fatalf("Expected the game to move on after saving")
+	}
+
+	if err := g.Load(1); err != nil {
+		t.Fatalf("Expected the game to load, got %v", err)
+	}
+	if loaded := capturePlayState(g); !reflect.DeepEqual(loaded, saved) {
+		t.Errorf("Expected the loaded game to be as saved\nsaved:  %+v\nloaded: %+v", saved, loaded)
+	}
+	if snapshotJSON(t, g) != savedSnapshot {
+		t.Errorf("Expected the loaded snapshot to match the saved one")
+	}
+
+	for range ticks {
+		g.Update(SimulationStep)
+	}
+	if replayed := capturePlayState(g); !reflect.DeepEqual(replayed, played) {
+		t.Errorf("Expected the replayed ticks to play out the same\nplayed:   %+v\nreplayed: %+v",
+			played, replayed)
+	}
+	if snapshotJSON(t, g) != playedSnapshot {
+		t.Errorf("Expected the replayed snapshot to match the one first played")
+	}
+}
+
+func TestSaveSlotsOutOfRangeFail(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	g := NewGame()
+	g.SetSeed(1)
+	g.Initialize(80, 24)
+
+	for _, slot := range []int{0, 4, -1} {
+		if err := g.Save(slot); err == nil {
+			t.Errorf("Expected saving to slot %d to fail", slot)
+		}
+		if err := g.Load(slot); err == nil {
+			t.Errorf("Expected loading slot %d to fail", slot)
+		}
+	}
+}
+
+func TestLoadingAnEmptySlotFails(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	g := NewGame()
+	g.SetSeed(1)
+	g.Initialize(80, 24)
+	before := snapshotJSON(t, g)
+
+	if err := g.Load(2); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("Expected loading an empty slot to fail as missing, got %v", err)
+	}
+	if snapshotJSON(t, g) != before {
+		t.Errorf("Expected a failed load to leave the game alone")
+	}
+}
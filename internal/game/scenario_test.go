@@ -0,0 +1,155 @@
+package game
+
+import (
+	"strings"
+	"testing"
+
+	"ecstemplate/internal/game/components"
+	"ecstemplate/internal/game/resources"
+	"ecstemplate/internal/game/ui/headless"
+	"ecstemplate/internal/input"
+	"ecstemplate/pkg/ecs"
+)
+
+// newScriptedGame starts a headless game played by the script
+func newScriptedGame(t *testing.T, script string) *Game {
+	t.Helper()
+	parsed, err := input.ParseScript(strings.NewReader(script))
+	if err != nil {
+		t.Fatalf("Expected the script to parse, got %v", err)
+	}
+
+	g := NewGame()
+	g.SetDisplayManager(&headless.DisplayManager{})
+	g.PlayScript(parsed)
+	g.SetSeed(1)
+	g.Initialize(80, 24)
+	return g
+}
+
+// runUntil updates the game a step at a time until the clock reaches the tick
+func runUntil(g *Game, tick uint64) {
+	clock := ecs.MustGetResource[ecs.Clock](g.world)
+	for clock.Frame < tick {
+		g.Update(SimulationStep)
+	}
+}
+
+// towerAt returns the tower standing at x,y, if there is one
+func towerAt(g *Game, x, y float64) (*components.TowerComponent, bool) {
+	towers := g.world.ComponentManager.GetAllEntitiesWithComponent(components.Tower)
+	for _, ent := range towers {
+		pos, found := g.componentAccess.GetPositionComponent(ent)
+		if found && pos.X == x && pos.Y == y {
+			return g.componentAccess.GetTowerComponent(ent)
+		}
+	}
+	return nil, false
+}
+
+func money(g *Game) float64 {
+	player := ecs.MustGetResource[resources.Player](g.world)
+	wallet, _ := g.componentAccess.GetWalletComponent(player.Entity)
+	return wallet.Money
+}
+
+func TestScenarioTowerWaitsUntilAffordable(t *testing.T) {
+	g := newScriptedGame(t, `
+		# The player starts with no money, so the tower waits for the first kill
+		1 move 12 8
+		1 build basic
+		1 place
+	`)
+
+	runUntil(g, 10)
+	if _, found := towerAt(g, 12, 8); found {
+		t.Fatalf("Expected no tower before the player can pay for it")
+	}
+
+	runUntil(g, 60*20)
+	tower, found := towerAt(g, 12, 8)
+	if !found {
+		t.Fatalf("Expected the tower to be built once the first enemy was killed")
+	}
+	if tower.Type != components.BasicTower {
+		t.Errorf("Expected a basic tower, got %q", tower.Type)
+	}
+	kills := ecs.MustGetResource[resources.Stats](g.world).Kills[components.BasicTower]
+	if want := 10*float64(kills) - 5; money(g) != want {
+		t.Errorf("Expected %g money after paying for the tower, got %g", want, money(g))
+	}
+}
+
+func TestScenarioBuildsWhilePaused(t *testing.T) {
+	g := newScriptedGame(t, `
+		1 press build_basic
+		5 pause
+		6 move 3 20
+		6 build heavy
+		6 place
+		7 cancel
+	`)
+
+	// Give the player enough for a heavy tower, which costs 15
+	player := ecs.MustGetResource[resources.Player](g.world)
+	wallet, _ := g.componentAccess.GetWalletComponent(player.Entity)
+	wallet.Money = 20
+
+	runUntil(g, 10)
+	clock := ecs.MustGetResource[ecs.Clock](g.world)
+	pausedAt := clock.Elapsed
+	runUntil(g, 30)
+	if clock.Elapsed != pausedAt {
+		t.Errorf("Expected simulation time to stop while paused, it went from %v to %v",
+			pausedAt, clock.Elapsed)
+	}
+
+	tower, found := towerAt(g, 3, 20)
+	if !found || tower.Type != components.HeavyTower {
+		t.Fatalf("Expected a heavy tower to be built while paused, got %v", tower)
+	}
+	if money(g) != 5 {
+		t.Errorf("Expected 5 money left, got %g", money(g))
+	}
+	if !ecs.MustGetResource[resources.Speed](g.world).Paused {
+		t.Errorf("Expected the game to still be paused")
+	}
+	if !g.GetInputManager().(*input.ScriptInputManager).Done() {
+		t.Errorf("Expected every command to have run")
+	}
+}
+
+func TestScenarioCallsWaveEarly(t *testing.T) {
+	g := newScriptedGame(t, `
+		# Wave 1 has 10 seconds to build, call it after 1
+		60 next_wave
+	`)
+
+	runUntil(g, 62)
+	wave := ecs.MustGetResource[resources.Wave](g.world)
+	if wave.Phase != resources.WaveSpawning {
+		t.Fatalf("Expected wave 1 to have been called, it is %s", wave.Phase)
+	}
+
+	// Just under 9 seconds were skipped, at 2 a second, rounded down
+	if money(g) != 17 {
+		t.Errorf("Expected a bonus of 17, got %g", money(g))
+	}
+}
+
+func TestScenarioUnknownTowerIsDropped(t *testing.T) {
+	g := newScriptedGame(t, `
+		1 move 12 8
+		1 build laser
+		1 place
+	`)
+
+	runUntil(g, 10)
+	player := ecs.MustGetResource[resources.Player](g.world)
+	if g.world.ComponentManager.HasComponent(player.Entity, components.CreateTowerIntent) {
+		t.Errorf("Expected the intent to build a tower there's no definition for to be dropped")
+	}
+	if _, found := towerAt(g, 12, 8); found {
+		t.Errorf("Expected no tower to be built")
+	}
+}
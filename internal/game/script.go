@@ -0,0 +1,23 @@
+package game
+
+import (
+	"os"
+
+	"ecstemplate/internal/input"
+)
+
+// PlayScript makes the game run the script's commands instead of reading input.
+// It must be called before Initialize.
+func (g *Game) PlayScript(script *input.Script) {
+	g.SetInputManager(input.NewScriptInputManager(g.world, script))
+}
+
+// LoadScript reads a script file, see input.Script for the format
+func LoadScript(path string) (*input.Script, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return input.ParseScript(file)
+}
@@ -0,0 +1,75 @@
+package game
+
+import (
+	"maps"
+	"time"
+
+	"ecstemplate/internal/game/components"
+	"ecstemplate/internal/game/resources"
+	"ecstemplate/pkg/ecs"
+)
+
+// Summary is how a run went, for tuning the game's numbers
+type Summary struct {
+	Seed         uint64
+	Waves        int // Waves started
+	WavesCleared int // Waves whose enemies are all gone, killed or leaked
+	Leaks        int // Enemies that reached the end of their path
+	Health       float64
+	Money        float64
+	Kills        map[components.TowerType]int
+	Elapsed      time.Duration // Simulation time
+	GameOver     bool
+}
+
+// Summary sums up the run so far
+func (g *Game) Summary() Summary {
+	player := ecs.MustGetResource[resources.Player](g.world)
+	health, _ := g.componentAccess.GetHealthComponent(player.Entity)
+	wallet, _ := g.componentAccess.GetWalletComponent(player.Entity)
+	wave := ecs.MustGetResource[resources.Wave](g.world)
+	stats := ecs.MustGetResource[resources.Stats](g.world)
+	gameState := ecs.MustGetResource[resources.GameState](g.world)
+
+	// The current wave hasn't started while it's being built for
+	started := wave.Number
+	if wave.Phase == resources.WaveBuilding {
+		started--
+	}
+
+	kills := maps.Clone(stats.Kills)
+	if kills == nil {
+		kills = make(map[components.TowerType]int)
+	}
+
+	return Summary{
+		Seed:         g.Seed(),
+		Waves:        started,
+		WavesCleared: wave.Completed,
+		Leaks:        stats.Leaks,
+		Health:       health.Current,
+		Money:        wallet.Money,
+		Kills:        kills,
+		Elapsed:      ecs.MustGetResource[ecs.Clock](g.world).Elapsed,
+		GameOver:     gameState.GameOver,
+	}
+}
+
+// Simulate runs the game as fast as it can, until the given number of waves have
+// been cleared, the waves run out, the player loses, or maxTicks simulation steps
+// have run.
+// It's meant for games without a screen or a player, see SetDisplayManager and
+// SetInputManager, and must be called after Initialize.
+func (g *Game) Simulate(waves int, maxTicks uint64) Summary {
+	clock := ecs.MustGetResource[ecs.Clock](g.world)
+	wave := ecs.MustGetResource[resources.Wave](g.world)
+	gameState := ecs.MustGetResource[resources.GameState](g.world)
+
+	for clock.Frame < maxTicks && !gameState.GameOver {
+		if wave.Completed >= waves || wave.Phase == resources.WaveComplete {
+			break
+		}
+		g.Update(SimulationStep)
+	}
+	return g.Summary()
+}
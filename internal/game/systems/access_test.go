@@ -0,0 +1,64 @@
+package systems
+
+import (
+	"log"
+	"reflect"
+	"slices"
+	"testing"
+	"unsafe"
+
+	"ecstemplate/internal/game/components"
+	"ecstemplate/internal/game/data"
+	"ecstemplate/pkg/ecs"
+)
+
+// touchedComponents returns the component types of the stores and queries a system
+// holds, which it reads or writes when it runs
+func touchedComponents(system any) []ecs.ComponentType {
+	touched := []ecs.ComponentType{}
+	value := reflect.ValueOf(system).Elem()
+	for i := range value.NumField() {
+		field := value.Field(i)
+		// The fields are unexported, so they're read through their address
+		field = reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem()
+		switch held := field.Interface().(type) {
+		case *ecs.Query:
+			filter := held.Filter()
+			touched = append(touched, filter.With...)
+			touched = append(touched, filter.Without...)
+			touched = append(touched, filter.Optional...)
+		case interface{ Type() ecs.ComponentType }:
+			touched = append(touched, held.Type())
+		}
+	}
+	return touched
+}
+
+func TestAccessListsEveryComponentTouched(t *testing.T) {
+	world := ecs.NewWorld(log.New(log.Writer(), t.Name()+": ", log.Flags()))
+	components.Register(world)
+	towers, err := data.Towers()
+	if err != nil {
+		t.Fatalf("Expected the towers to load, got %v", err)
+	}
+
+	systems := []ecs.AccessDeclarer{
+		NewCollisionSystem(world),
+		NewEnemyMovementSystem(world),
+		NewPositionHistorySystem(world),
+		NewProjectileSystem(world),
+		NewProjectileCreationSystem(world),
+		NewTowerFactorySystem(world, towers),
+		NewTowerTargetingSystem(world),
+		NewWaveSystem(world),
+	}
+	for _, system := range systems {
+		access := system.Access()
+		declared := slices.Concat(access.Reads, access.Writes)
+		for _, componentType := range touchedComponents(system) {
+			if !slices.Contains(declared, componentType) {
+				t.Errorf("Expected %T to declare component %v", system, componentType)
+			}
+		}
+	}
+}
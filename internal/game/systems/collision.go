@@ -1,77 +1,123 @@
 package systems
 
 import (
+	"reflect"
+	"slices"
+
 	"ecstemplate/internal/game/components"
 	"ecstemplate/internal/game/events"
+	"ecstemplate/internal/game/resources"
 	"ecstemplate/pkg/ecs"
 )
 
 // CollisionSystem checks for collisions between projectiles and enemies, and handles them accordingly
 type CollisionSystem struct {
-	ComponentAccess *components.ComponentAccess
+	flying      *ecs.Query
+	targets     *ecs.Query
+	projectiles *ecs.Store[components.ProjectileComponent]
+	enemies     *ecs.Store[components.EnemyComponent]
+	positions   *ecs.Store[components.PositionComponent]
+	boxes       *ecs.Store[components.BoundingBoxComponent]
+	healths     *ecs.Store[components.HealthComponent]
+	wallets     *ecs.Store[components.WalletComponent]
 }
 
-func (s *CollisionSystem) Update(world *ecs.World, deltaTime float64) {
-	// Get the projectiles
-	projectileEnts := world.ComponentManager.GetAllEntitiesWithComponent(components.Projectile)
+func NewCollisionSystem(world *ecs.World) *CollisionSystem {
+	return &CollisionSystem{
+		flying: world.ComponentManager.NewQuery(
+			[]ecs.ComponentType{
+				components.Projectile,
+				components.Position,
+				components.BoundingBox,
+			},
+		),
+		targets: world.ComponentManager.NewQuery(
+			[]ecs.ComponentType{
+				components.Enemy,
+				components.Position,
+				components.BoundingBox,
+				components.Health,
+			},
+		),
+		projectiles: ecs.NewStore[components.ProjectileComponent](world),
+		enemies:     ecs.NewStore[components.EnemyComponent](world),
+		positions:   ecs.NewStore[components.PositionComponent](world),
+		boxes:       ecs.NewStore[components.BoundingBoxComponent](world),
+		healths:     ecs.NewStore[components.HealthComponent](world),
+		wallets:     ecs.NewStore[components.WalletComponent](world),
+	}
+}
 
-	// Get the enemies
-	enemyEnts := world.ComponentManager.GetAllEntitiesWithComponent(components.Enemy)
+func (s *CollisionSystem) Access() ecs.SystemAccess {
+	return ecs.SystemAccess{
+		Reads: []ecs.ComponentType{
+			components.Position,
+			components.BoundingBox,
+			components.Enemy,
+		},
+		Writes: []ecs.ComponentType{
+			components.Health,
+			components.Wallet,
+			components.Projectile,
+		},
+		ResourceReads: []reflect.Type{ecs.ResourceOf[resources.Player]()},
+	}
+}
 
+func (s *CollisionSystem) Update(world *ecs.World, deltaTime float64) {
 	// Loop through all projectiles
-	for _, projectileEnt := range projectileEnts {
-		projPos, h1 := s.ComponentAccess.GetPositionComponent(projectileEnt)
-		projBoundingBox, h2 := s.ComponentAccess.GetBoundingBoxComponent(projectileEnt)
-
-		if h1 == false || h2 == false {
-			continue
-		}
+	for _, projectileEnt := range s.flying.Entities() {
+		projPos, _ := s.positions.Get(projectileEnt)
+		projBoundingBox, _ := s.boxes.Get(projectileEnt)
+		proj, _ := s.projectiles.Get(projectileEnt)
 
 		// Loop through all enemies
-		for _, enemyEnt := range enemyEnts {
-			enemyPos, h3 := s.ComponentAccess.GetPositionComponent(enemyEnt)
-			enemyBoundingBox, h4 := s.ComponentAccess.GetBoundingBoxComponent(enemyEnt)
-
-			if h3 == false || h4 == false {
+		for _, enemyEnt := range s.targets.Entities() {
+			enemyPos, _ := s.positions.Get(enemyEnt)
+			enemyBoundingBox, _ := s.boxes.Get(enemyEnt)
+			enemyHealth, _ := s.healths.Get(enemyEnt)
+
+			// Enemies killed earlier this frame stay in the query until the commands are
+			// applied, and piercing projectiles pass through enemies they've already hit
+			if enemyHealth.Current <= 0 || slices.Contains(proj.Hits, enemyEnt) {
 				continue
 			}
 
 			// Check if the projectile is colliding with the enemy
 			if isColliding(*projPos, *enemyPos, *projBoundingBox, *enemyBoundingBox) {
-				proj, _ := s.ComponentAccess.GetProjectileComponent(projectileEnt)
-				enemyHealth, _ := s.ComponentAccess.GetHealthComponent(enemyEnt)
-
-				// Remove the projectile
-				world.ComponentManager.RemoveAllComponents(projectileEnt)
-				world.EntityManager.RemoveEntity(projectileEnt)
+				// Remove the projectile, unless it can pierce the enemy
+				if proj.Pierce > 0 {
+					proj.Pierce--
+					proj.Hits = append(proj.Hits, enemyEnt)
+					s.projectiles.MarkChanged(projectileEnt)
+				} else {
+					world.Commands().RemoveEntity(projectileEnt)
+				}
 
 				// Decrease the enemy health
 				enemyHealth.Current -= proj.Damage
+				s.healths.MarkChanged(enemyEnt)
 
 				// Check if the enemy is dead
 				if enemyHealth.Current <= 0 {
-					// Get the player and wallet components
-					playerEnts := world.ComponentManager.GetAllEntitiesWithComponent(
-						components.Player,
-					)
-					if len(playerEnts) != 1 {
-						continue
-					}
-					playerEnt := playerEnts[0]
-					wallet, _ := s.ComponentAccess.GetWalletComponent(playerEnt)
-
-					enemy, _ := s.ComponentAccess.GetEnemyComponent(enemyEnt)
+					// Get the player's wallet
+					player := ecs.MustGetResource[resources.Player](world)
+					wallet, _ := s.wallets.Get(player.Entity)
+
+					enemy, _ := s.enemies.Get(enemyEnt)
 					wallet.Money += enemy.Reward
+					s.wallets.MarkChanged(player.Entity)
 
-					// Queue enemy killed event
-					world.QueueEvent(&events.EnemyKilledEvent{
-						EnemyType: "enemy",
+					// Send enemy killed event
+					ecs.Send(world, events.EnemyKilledEvent{
+						Enemy:     enemyEnt,
+						EnemyType: enemy.Type,
 						Reward:    enemy.Reward,
+						Killer:    proj.Shooter,
 					})
 
 					// Remove the enemy
-					world.ComponentManager.RemoveAllComponents(enemyEnt)
-					world.EntityManager.RemoveEntity(enemyEnt)
+					world.Commands().RemoveEntity(enemyEnt)
 				}
 				break
 			}
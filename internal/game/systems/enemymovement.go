@@ -2,59 +2,77 @@ package systems
 
 import (
 	"math"
+	"reflect"
 
 	"ecstemplate/internal/game/components"
 	"ecstemplate/internal/game/events"
+	"ecstemplate/internal/game/resources"
 	"ecstemplate/pkg/ecs"
 )
 
 // EnemyMovementSystem is a system that moves enemies along a path.
 type EnemyMovementSystem struct {
-	ComponentAccess *components.ComponentAccess
+	runners     *ecs.Query
+	enemies     *ecs.Store[components.EnemyComponent]
+	positions   *ecs.Store[components.PositionComponent]
+	pathFollows *ecs.Store[components.PathFollowComponent]
+	paths       *ecs.Store[components.PathComponent]
 }
 
-func (s *EnemyMovementSystem) Update(world *ecs.World, deltaTime float64) {
-	// Get all entities with an enemy, position, and path component.
-	runnerEnts := world.ComponentManager.GetAllEntitiesWithComponents(
-		[]ecs.ComponentType{
-			components.Position,
-			components.Health,
+func NewEnemyMovementSystem(world *ecs.World) *EnemyMovementSystem {
+	return &EnemyMovementSystem{
+		runners: world.ComponentManager.NewQuery(
+			[]ecs.ComponentType{
+				components.Position,
+				components.Health,
+				components.Enemy,
+				components.PathFollow,
+				components.Renderable,
+			},
+		),
+		enemies:     ecs.NewStore[components.EnemyComponent](world),
+		positions:   ecs.NewStore[components.PositionComponent](world),
+		pathFollows: ecs.NewStore[components.PathFollowComponent](world),
+		paths:       ecs.NewStore[components.PathComponent](world),
+	}
+}
+
+func (s *EnemyMovementSystem) Access() ecs.SystemAccess {
+	return ecs.SystemAccess{
+		Reads: []ecs.ComponentType{
 			components.Enemy,
-			components.PathFollow,
+			components.Health,
 			components.Renderable,
+			components.Path,
 		},
-	)
-
-	pathEnts := world.ComponentManager.GetAllEntitiesWithComponent(components.Path)
-	if len(pathEnts) == 0 {
-		// No paths exist in the world
-		return
+		Writes:        []ecs.ComponentType{components.Position, components.PathFollow},
+		ResourceReads: []reflect.Type{ecs.ResourceOf[resources.Paths]()},
 	}
+}
+
+func (s *EnemyMovementSystem) Update(world *ecs.World, deltaTime float64) {
+	// Get all entities with an enemy, position, and path component.
+	runnerEnts := s.runners.Entities()
+
+	paths := ecs.MustGetResource[resources.Paths](world)
 
 	for _, runnerEnt := range runnerEnts {
 		// Get the enemy, position, and path components for the entity.
-		enemy, _ := s.ComponentAccess.GetEnemyComponent(runnerEnt)
-		position, _ := s.ComponentAccess.GetPositionComponent(runnerEnt)
-		pathFollow, _ := s.ComponentAccess.GetPathFollowComponent(runnerEnt)
+		enemy, _ := s.enemies.Get(runnerEnt)
+		position, _ := s.positions.GetMut(runnerEnt)
+		pathFollow, _ := s.pathFollows.GetMut(runnerEnt)
 
 		// Get the path for the enemy
-		var path *components.PathComponent
-		for _, ent := range pathEnts {
-			p, _ := s.ComponentAccess.GetPathComponent(ent)
-			if p.ID == pathFollow.PathID {
-				path, _ = s.ComponentAccess.GetPathComponent(ent)
-				break
-			}
-		}
-
-		if path == nil {
+		pathEnt, found := paths.Get(pathFollow.PathID)
+		if !found {
 			// The path for the enemy does not exist
-			return
+			continue
 		}
+		path, _ := s.paths.Get(pathEnt)
 
 		if pathFollow.WaypointIndex >= len(path.Waypoints)-1 {
 			// The enemy has reached the end of the path
-			world.QueueEvent(&events.EnemyReachedEndEvent{
+			ecs.Send(world, events.EnemyReachedEndEvent{
 				Ent: runnerEnt,
 			})
 			continue
@@ -76,7 +94,7 @@ func (s *EnemyMovementSystem) Update(world *ecs.World, deltaTime float64) {
 			pathFollow.WaypointIndex++
 			if pathFollow.WaypointIndex >= len(path.Waypoints) {
 				// The enemy has reached the end of the path
-				world.QueueEvent(&events.EnemyReachedEndEvent{
+				ecs.Send(world, events.EnemyReachedEndEvent{
 					Ent: runnerEnt,
 				})
 				continue
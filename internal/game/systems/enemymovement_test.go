@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"ecstemplate/internal/game/components"
+	"ecstemplate/internal/game/resources"
 	"ecstemplate/pkg/ecs"
 )
 
@@ -19,6 +20,7 @@ func TestEnemyMovementSystem(t *testing.T) {
 	}
 
 	componentAccess := components.NewComponentAccess(world)
+	resources.TrackPaths(world)
 
 	// Create test path
 	pathEnt := world.EntityManager.CreateEntity()
@@ -61,9 +63,7 @@ func TestEnemyMovementSystem(t *testing.T) {
 	world.ComponentManager.AddComponent(enemyEnt, components.Renderable, renderableComponent)
 
 	// Create the system
-	system := &EnemyMovementSystem{
-		ComponentAccess: componentAccess,
-	}
+	system := NewEnemyMovementSystem(world)
 
 	RunSimulation(system, world, 18, 60.0)
 
@@ -86,8 +86,11 @@ func TestEnemyMovementSystem(t *testing.T) {
 func RunSimulation(system ecs.System, world *ecs.World, seconds float64, fps float64) {
 	totalFrames := int(seconds * fps)
 	deltaTime := 1.0 / fps
+	clock := ecs.MustGetResource[ecs.Clock](world)
 
 	for range totalFrames {
+		clock.Advance(deltaTime)
 		system.Update(world, deltaTime)
+		world.Commands().Apply()
 	}
 }
@@ -0,0 +1,43 @@
+package systems
+
+import (
+	"ecstemplate/internal/game/components"
+	"ecstemplate/pkg/ecs"
+)
+
+// PositionHistorySystem records where moving entities are before each simulation
+// step moves them, so the renderer can draw them between steps
+type PositionHistorySystem struct {
+	movers    *ecs.Query
+	positions *ecs.Store[components.PositionComponent]
+	previous  *ecs.Store[components.PreviousPositionComponent]
+}
+
+func NewPositionHistorySystem(world *ecs.World) *PositionHistorySystem {
+	return &PositionHistorySystem{
+		movers: world.ComponentManager.NewQuery(
+			[]ecs.ComponentType{
+				components.Position,
+				components.PreviousPosition,
+			},
+		),
+		positions: ecs.NewStore[components.PositionComponent](world),
+		previous:  ecs.NewStore[components.PreviousPositionComponent](world),
+	}
+}
+
+func (s *PositionHistorySystem) Access() ecs.SystemAccess {
+	return ecs.SystemAccess{
+		Reads:  []ecs.ComponentType{components.Position},
+		Writes: []ecs.ComponentType{components.PreviousPosition},
+	}
+}
+
+func (s *PositionHistorySystem) Update(world *ecs.World, deltaTime float64) {
+	for _, moverEnt := range s.movers.Entities() {
+		position, _ := s.positions.Get(moverEnt)
+		previous, _ := s.previous.GetMut(moverEnt)
+		previous.X = position.X
+		previous.Y = position.Y
+	}
+}
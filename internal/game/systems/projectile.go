@@ -1,30 +1,64 @@
 package systems
 
 import (
+	"reflect"
+
 	"ecstemplate/internal/game/components"
+	"ecstemplate/internal/game/resources"
 	"ecstemplate/pkg/ecs"
 )
 
-// ProjectileSystem handles the movement of projectiles
+// ProjectileSystem handles the movement of projectiles, and removes them once they
+// leave the play area or their lifetime is up
 type ProjectileSystem struct {
-	ComponentAccess *components.ComponentAccess
+	flying      *ecs.Query
+	projectiles *ecs.Store[components.ProjectileComponent]
+	positions   *ecs.Store[components.PositionComponent]
+	velocities  *ecs.Store[components.VelocityComponent]
 }
 
-func (s *ProjectileSystem) Update(world *ecs.World, deltaTime float64) {
-	// Get the screen
-	displayEnts := world.ComponentManager.GetAllEntitiesWithComponent(components.Display)
-	if len(displayEnts) != 1 {
-		return
+func NewProjectileSystem(world *ecs.World) *ProjectileSystem {
+	return &ProjectileSystem{
+		flying: world.ComponentManager.NewQuery(
+			[]ecs.ComponentType{
+				components.Projectile,
+				components.Position,
+				components.Velocity,
+			},
+		),
+		projectiles: ecs.NewStore[components.ProjectileComponent](world),
+		positions:   ecs.NewStore[components.PositionComponent](world),
+		velocities:  ecs.NewStore[components.VelocityComponent](world),
+	}
+}
+
+func (s *ProjectileSystem) Access() ecs.SystemAccess {
+	return ecs.SystemAccess{
+		Reads:  []ecs.ComponentType{components.Projectile, components.Velocity},
+		Writes: []ecs.ComponentType{components.Position},
+		ResourceReads: []reflect.Type{
+			ecs.ResourceOf[resources.Display](),
+			ecs.ResourceOf[ecs.Clock](),
+		},
 	}
-	display, _ := s.ComponentAccess.GetDisplayComponent(displayEnts[0])
+}
 
-	// Get the projectiles
-	projectileEnts := world.ComponentManager.GetAllEntitiesWithComponent(components.Projectile)
+func (s *ProjectileSystem) Update(world *ecs.World, deltaTime float64) {
+	// Get the screen
+	display := ecs.MustGetResource[resources.Display](world)
+	clock := ecs.MustGetResource[ecs.Clock](world)
 
 	// Loop through all projectiles
-	for _, projectileEnt := range projectileEnts {
-		projPos, _ := s.ComponentAccess.GetPositionComponent(projectileEnt)
-		projVel, _ := s.ComponentAccess.GetVelocityComponent(projectileEnt)
+	for _, projectileEnt := range s.flying.Entities() {
+		projPos, _ := s.positions.GetMut(projectileEnt)
+		projVel, _ := s.velocities.Get(projectileEnt)
+		proj, _ := s.projectiles.Get(projectileEnt)
+
+		// Check if the projectile has expired
+		if proj.Expires > 0 && clock.Elapsed >= proj.Expires {
+			world.Commands().RemoveEntity(projectileEnt)
+			continue
+		}
 
 		// Move the projectile
 		projPos.X += projVel.X * deltaTime
@@ -33,8 +67,7 @@ func (s *ProjectileSystem) Update(world *ecs.World, deltaTime float64) {
 		// Check if the projectile is out of bounds
 		if projPos.X < 0 || projPos.X > float64(display.Width) ||
 			projPos.Y < 0 || projPos.Y > float64(display.Height) {
-			world.ComponentManager.RemoveAllComponents(projectileEnt)
-			world.EntityManager.RemoveEntity(projectileEnt)
+			world.Commands().RemoveEntity(projectileEnt)
 			continue
 		}
 	}
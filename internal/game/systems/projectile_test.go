@@ -0,0 +1,132 @@
+package systems
+
+import (
+	"log"
+	"math"
+	"testing"
+	"time"
+
+	"ecstemplate/internal/game/components"
+	"ecstemplate/internal/game/resources"
+	"ecstemplate/pkg/ecs"
+)
+
+func newProjectileTestWorld(t *testing.T) (*ecs.World, *components.ComponentAccess) {
+	logger := log.New(log.Writer(), t.Name()+": ", log.Flags())
+	world := ecs.NewWorld(logger)
+	components.Register(world)
+	ecs.InsertResource(world, &resources.Display{Width: 80, Height: 24})
+
+	playerEnt := world.EntityManager.CreateEntity()
+	world.ComponentManager.AddComponent(playerEnt, components.Wallet,
+		&components.WalletComponent{})
+	ecs.InsertResource(world, &resources.Player{Entity: playerEnt})
+	return world, components.NewComponentAccess(world)
+}
+
+func addTestEnemy(world *ecs.World, x, y float64) ecs.Entity {
+	enemyEnt := world.EntityManager.CreateEntity()
+	world.ComponentManager.AddComponent(enemyEnt, components.Enemy,
+		&components.EnemyComponent{Type: "basic"})
+	world.ComponentManager.AddComponent(enemyEnt, components.Position,
+		&components.PositionComponent{X: x, Y: y})
+	world.ComponentManager.AddComponent(enemyEnt, components.BoundingBox,
+		&components.BoundingBoxComponent{Width: 1, Height: 1})
+	world.ComponentManager.AddComponent(enemyEnt, components.Health,
+		&components.HealthComponent{Current: 10, Max: 10})
+	return enemyEnt
+}
+
+func TestProjectileCreationUsesShooterSpec(t *testing.T) {
+	world, componentAccess := newProjectileTestWorld(t)
+	enemyEnt := addTestEnemy(world, 6, 8)
+
+	towerEnt := world.EntityManager.CreateEntity()
+	world.ComponentManager.AddComponent(towerEnt, components.Position,
+		&components.PositionComponent{X: 0, Y: 0})
+	world.ComponentManager.AddComponent(towerEnt, components.ProjectileSpec,
+		&components.ProjectileSpecComponent{
+			Damage:   4,
+			Speed:    10,
+			Symbol:   "*",
+			Size:     2,
+			Lifetime: time.Second / 2,
+			Pierce:   1,
+		})
+	world.ComponentManager.AddComponent(towerEnt, components.ShootIntent,
+		&components.ShootIntentComponent{Shooter: towerEnt, Target: enemyEnt})
+
+	RunSimulation(NewProjectileCreationSystem(world), world, 1.0/60, 60.0)
+
+	projectiles := world.ComponentManager.GetAllEntitiesWithComponent(components.Projectile)
+	if len(projectiles) != 1 {
+		t.Fatalf("Expected one projectile, got %d", len(projectiles))
+	}
+	projectileEnt := projectiles[0]
+	proj, _ := componentAccess.GetProjectileComponent(projectileEnt)
+	clock := ecs.MustGetResource[ecs.Clock](world)
+	if proj.Damage != 4 || proj.Speed != 10 || proj.Pierce != 1 ||
+		proj.Expires != clock.Elapsed+time.Second/2 || proj.Shooter != towerEnt {
+		t.Errorf("Expected the projectile to follow the tower's spec, got %+v", proj)
+	}
+
+	velocity, _ := componentAccess.GetVelocityComponent(projectileEnt)
+	box, _ := componentAccess.GetBoundingBoxComponent(projectileEnt)
+	renderable, _ := componentAccess.GetRenderableComponent(projectileEnt)
+	speed := math.Hypot(velocity.X, velocity.Y)
+	if math.Abs(speed-10) > 1e-9 || box.Width != 2 || box.Height != 2 || renderable.Symbol != "*" {
+		t.Errorf("Expected a size 2 '*' flying at 10, got %+v %+v at %v", box, renderable, speed)
+	}
+}
+
+func TestProjectilePiercesAndExpires(t *testing.T) {
+	world, componentAccess := newProjectileTestWorld(t)
+	first := addTestEnemy(world, 5, 5)
+	second := addTestEnemy(world, 5.5, 5)
+
+	// A projectile overlapping both enemies, that can pass through one of them
+	projectileEnt := world.EntityManager.CreateEntity()
+	world.ComponentManager.AddComponent(projectileEnt, components.Projectile,
+		&components.ProjectileComponent{Damage: 3, Pierce: 1, Expires: time.Second})
+	world.ComponentManager.AddComponent(projectileEnt, components.Position,
+		&components.PositionComponent{X: 5, Y: 5})
+	world.ComponentManager.AddComponent(projectileEnt, components.BoundingBox,
+		&components.BoundingBoxComponent{Width: 1, Height: 1})
+	world.ComponentManager.AddComponent(projectileEnt, components.Velocity,
+		&components.VelocityComponent{})
+
+	// It hits one enemy a step, and never the same one twice
+	collision := NewCollisionSystem(world)
+	RunSimulation(collision, world, 1.0/60, 60.0)
+	if !world.EntityManager.IsAlive(projectileEnt) {
+		t.Fatalf("Expected the projectile to pierce the first enemy")
+	}
+	RunSimulation(collision, world, 1.0/60, 60.0)
+	if world.EntityManager.IsAlive(projectileEnt) {
+		t.Fatalf("Expected the projectile to be removed after its second hit")
+	}
+	for _, enemyEnt := range []ecs.Entity{first, second} {
+		if health, _ := componentAccess.GetHealthComponent(enemyEnt); health.Current != 7 {
+			t.Errorf("Expected each enemy to be hit once, for 7 health, got %v", health.Current)
+		}
+	}
+
+	// One that hits nothing is removed when its lifetime is up
+	expiringEnt := world.EntityManager.CreateEntity()
+	world.ComponentManager.AddComponent(expiringEnt, components.Projectile,
+		&components.ProjectileComponent{Expires: time.Second})
+	world.ComponentManager.AddComponent(expiringEnt, components.Position,
+		&components.PositionComponent{X: 40, Y: 12})
+	world.ComponentManager.AddComponent(expiringEnt, components.Velocity,
+		&components.VelocityComponent{})
+
+	projectiles := NewProjectileSystem(world)
+	RunSimulation(projectiles, world, 0.9, 60.0)
+	if !world.EntityManager.IsAlive(expiringEnt) {
+		t.Fatalf("Expected the projectile to last a second")
+	}
+	RunSimulation(projectiles, world, 0.1, 60.0)
+	if world.EntityManager.IsAlive(expiringEnt) {
+		t.Errorf("Expected the projectile to be removed after a second")
+	}
+}
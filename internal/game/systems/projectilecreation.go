@@ -2,46 +2,92 @@ package systems
 
 import (
 	"math"
+	"reflect"
+	"time"
 
 	"ecstemplate/internal/game/components"
 	"ecstemplate/pkg/ecs"
 )
 
-// ProjectileCreationSystem handles the shootintents and creates the projectiles accordingly
+// ProjectileCreationSystem handles the shootintents and creates the projectiles accordingly,
+// as described by the shooter's ProjectileSpec
 type ProjectileCreationSystem struct {
-	ComponentAccess *components.ComponentAccess
+	shooters     *ecs.Query
+	shootIntents *ecs.Store[components.ShootIntentComponent]
+	positions    *ecs.Store[components.PositionComponent]
+	specs        *ecs.Store[components.ProjectileSpecComponent]
+}
+
+func NewProjectileCreationSystem(world *ecs.World) *ProjectileCreationSystem {
+	return &ProjectileCreationSystem{
+		shooters: world.ComponentManager.NewQuery(
+			[]ecs.ComponentType{components.ShootIntent},
+		),
+		shootIntents: ecs.NewStore[components.ShootIntentComponent](world),
+		positions:    ecs.NewStore[components.PositionComponent](world),
+		specs:        ecs.NewStore[components.ProjectileSpecComponent](world),
+	}
+}
+
+func (s *ProjectileCreationSystem) Access() ecs.SystemAccess {
+	return ecs.SystemAccess{
+		Reads: []ecs.ComponentType{
+			components.ShootIntent,
+			components.Position,
+			components.ProjectileSpec,
+		},
+		ResourceReads: []reflect.Type{ecs.ResourceOf[ecs.Clock]()},
+	}
 }
 
 func (s *ProjectileCreationSystem) Update(world *ecs.World, deltaTime float64) {
 	// Get all entities with a shoot intent (should only be towers)
-	shootIntentEnts := world.ComponentManager.GetAllEntitiesWithComponent(components.ShootIntent)
+	shootIntentEnts := s.shooters.Entities()
+
+	clock := ecs.MustGetResource[ecs.Clock](world)
 
 	// Loop through all shoot intents
 	for _, shootIntentEnt := range shootIntentEnts {
-		shootIntent, _ := s.ComponentAccess.GetShootIntentComponent(shootIntentEnt)
+		shootIntent, _ := s.shootIntents.Get(shootIntentEnt)
 
 		// Get the shooter's position
-		shooterPos, _ := s.ComponentAccess.GetPositionComponent(shootIntent.Shooter)
+		shooterPos, _ := s.positions.Get(shootIntent.Shooter)
+
+		// Drop the shot if the target was destroyed since it was aimed at, or the
+		// shooter has nothing to fire
+		spec, found := s.specs.Get(shootIntent.Shooter)
+		if !found || !world.EntityManager.IsAlive(shootIntent.Target) {
+			world.Commands().RemoveComponent(shootIntentEnt, components.ShootIntent)
+			continue
+		}
 
 		// Get the target's position
-		targetPos, _ := s.ComponentAccess.GetPositionComponent(shootIntent.Target)
+		targetPos, _ := s.positions.Get(shootIntent.Target)
 
 		// Get the angle between the two
 		angle := calcAngleBetweenPoints(*shooterPos, *targetPos)
 
+		var expires time.Duration
+		if spec.Lifetime > 0 {
+			expires = clock.Elapsed + spec.Lifetime
+		}
+
 		// Create the projectile entity with the velocity vector
-		baseProjectileSpeed := 8.0 // This should be a constant
-		projectileEnt := world.EntityManager.CreateEntity()
-		world.ComponentManager.AddComponent(
+		commands := world.Commands()
+		projectileEnt := commands.CreateEntity()
+		commands.AddComponent(
 			projectileEnt,
 			components.Projectile,
 			&components.ProjectileComponent{
-				Damage:       1,
-				Speed:        baseProjectileSpeed,
+				Damage:       spec.Damage,
+				Speed:        spec.Speed,
+				Pierce:       spec.Pierce,
+				Expires:      expires,
 				TargetEntity: shootIntent.Target,
+				Shooter:      shootIntent.Shooter,
 			},
 		)
-		world.ComponentManager.AddComponent(
+		commands.AddComponent(
 			projectileEnt,
 			components.Position,
 			&components.PositionComponent{
@@ -49,31 +95,39 @@ func (s *ProjectileCreationSystem) Update(world *ecs.World, deltaTime float64) {
 				Y: shooterPos.Y,
 			},
 		)
-		world.ComponentManager.AddComponent(
+		commands.AddComponent(
+			projectileEnt,
+			components.PreviousPosition,
+			&components.PreviousPositionComponent{
+				X: shooterPos.X,
+				Y: shooterPos.Y,
+			},
+		)
+		commands.AddComponent(
 			projectileEnt,
 			components.BoundingBox,
 			&components.BoundingBoxComponent{
-				Width:  1,
-				Height: 1,
+				Width:  spec.Size,
+				Height: spec.Size,
 			},
 		)
-		world.ComponentManager.AddComponent(
+		commands.AddComponent(
 			projectileEnt,
 			components.Velocity,
 			&components.VelocityComponent{
-				X: math.Cos(angle) * baseProjectileSpeed,
-				Y: math.Sin(angle) * baseProjectileSpeed,
+				X: math.Cos(angle) * spec.Speed,
+				Y: math.Sin(angle) * spec.Speed,
 			},
 		)
-		world.ComponentManager.AddComponent(
+		commands.AddComponent(
 			projectileEnt,
 			components.Renderable,
 			&components.RenderableComponent{
-				Symbol: "o",
+				Symbol: spec.Symbol,
 			},
 		)
 
 		// Remove the shoot intent component from the tower
-		world.ComponentManager.RemoveComponent(shootIntentEnt, components.ShootIntent)
+		commands.RemoveComponent(shootIntentEnt, components.ShootIntent)
 	}
 }
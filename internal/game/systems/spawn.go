@@ -0,0 +1,93 @@
+package systems
+
+import (
+	"fmt"
+
+	"ecstemplate/internal/game/components"
+	"ecstemplate/internal/game/resources"
+	"ecstemplate/pkg/ecs"
+)
+
+// SpawnEnemy queues an enemy of the given type at the start of the path, using its
+// archetype from the Enemies resource. The enemy is part of the given wave, or none
+// if it's 0. It's created when the world's commands are applied.
+func SpawnEnemy(world *ecs.World, enemyType, pathID string, wave int) (ecs.Entity, error) {
+	archetype, found := ecs.MustGetResource[resources.Enemies](world).Get(enemyType)
+	if !found {
+		return ecs.NoEntity, fmt.Errorf("unknown enemy type %q", enemyType)
+	}
+	pathEnt, found := ecs.MustGetResource[resources.Paths](world).Get(pathID)
+	if !found {
+		return ecs.NoEntity, fmt.Errorf("unknown path %q", pathID)
+	}
+	path, _ := components.GetComponentT[*components.PathComponent](
+		world, pathEnt, components.Path,
+	)
+	start := path.Waypoints[0]
+
+	// Create the enemy entity
+	commands := world.Commands()
+	enemyEnt := commands.CreateEntity()
+	commands.AddComponent(
+		enemyEnt,
+		components.Enemy,
+		&components.EnemyComponent{
+			Type:      archetype.Type,
+			Speed:     archetype.Speed,
+			Reward:    archetype.Reward,
+			Damage:    archetype.Damage,
+			Abilities: archetype.Abilities,
+			Wave:      wave,
+		},
+	)
+	commands.AddComponent(
+		enemyEnt,
+		components.BoundingBox,
+		&components.BoundingBoxComponent{
+			Width:  archetype.Width,
+			Height: archetype.Height,
+		},
+	)
+	commands.AddComponent(
+		enemyEnt,
+		components.Position,
+		&components.PositionComponent{
+			X: start.X,
+			Y: start.Y,
+		},
+	)
+	commands.AddComponent(
+		enemyEnt,
+		components.PreviousPosition,
+		&components.PreviousPositionComponent{
+			X: start.X,
+			Y: start.Y,
+		},
+	)
+	commands.AddComponent(
+		enemyEnt,
+		components.Health,
+		&components.HealthComponent{
+			Current: archetype.Health,
+			Max:     archetype.Health,
+		},
+	)
+	commands.AddComponent(
+		enemyEnt,
+		components.PathFollow,
+		&components.PathFollowComponent{
+			PathID:        pathID,
+			WaypointIndex: 0,
+		},
+	)
+	commands.AddComponent(
+		enemyEnt,
+		components.Renderable,
+		&components.RenderableComponent{
+			Symbol: archetype.Symbol,
+			Color:  archetype.Color,
+		},
+	)
+
+	return enemyEnt, nil
+}
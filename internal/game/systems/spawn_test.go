@@ -0,0 +1,73 @@
+package systems
+
+import (
+	"log"
+	"slices"
+	"testing"
+
+	"ecstemplate/internal/game/components"
+	"ecstemplate/internal/game/resources"
+	"ecstemplate/pkg/ecs"
+)
+
+func TestSpawnEnemyUsesArchetype(t *testing.T) {
+	logger := log.New(log.Writer(), "TestSpawnEnemyUsesArchetype: ", log.Flags())
+	world := ecs.NewWorld(logger)
+	components.Register(world)
+	resources.TrackPaths(world)
+	componentAccess := components.NewComponentAccess(world)
+
+	pathEnt := world.EntityManager.CreateEntity()
+	world.ComponentManager.AddComponent(pathEnt, components.Path, &components.PathComponent{
+		ID:        "path",
+		Waypoints: []components.PositionComponent{{X: 3, Y: 4}, {X: 10, Y: 4}},
+	})
+	ecs.InsertResource(world, &resources.Enemies{Archetypes: map[string]resources.EnemyArchetype{
+		"ghost": {
+			Type:      "ghost",
+			Speed:     1.5,
+			Health:    12,
+			Reward:    7,
+			Damage:    4,
+			Width:     2,
+			Height:    3,
+			Symbol:    "G",
+			Color:     "#FFFFFF",
+			Abilities: []string{"invisible"},
+		},
+	}})
+
+	enemyEnt, err := SpawnEnemy(world, "ghost", "path", 2)
+	if err != nil {
+		t.Fatalf("Expected the enemy to spawn, got %v", err)
+	}
+	world.Commands().Apply()
+
+	enemy, _ := componentAccess.GetEnemyComponent(enemyEnt)
+	if enemy.Speed != 1.5 || enemy.Reward != 7 || enemy.Damage != 4 || enemy.Wave != 2 ||
+		!enemy.HasAbility("invisible") {
+		t.Errorf("Expected the enemy to match its archetype, got %+v", enemy)
+	}
+	health, _ := componentAccess.GetHealthComponent(enemyEnt)
+	box, _ := componentAccess.GetBoundingBoxComponent(enemyEnt)
+	pos, _ := componentAccess.GetPositionComponent(enemyEnt)
+	renderable, _ := componentAccess.GetRenderableComponent(enemyEnt)
+	if health.Max != 12 || box.Width != 2 || box.Height != 3 || pos.X != 3 || pos.Y != 4 ||
+		renderable.Symbol != "G" || renderable.Color != "#FFFFFF" {
+		t.Errorf("Expected the enemy to be made from its archetype, got %+v %+v %+v %+v",
+			health, box, pos, renderable)
+	}
+
+	// Unknown types and paths spawn nothing
+	if _, err := SpawnEnemy(world, "dragon", "path", 0); err == nil {
+		t.Errorf("Expected an unknown enemy type to fail")
+	}
+	if _, err := SpawnEnemy(world, "ghost", "nowhere", 0); err == nil {
+		t.Errorf("Expected an unknown path to fail")
+	}
+	world.Commands().Apply()
+	enemies := world.ComponentManager.GetAllEntitiesWithComponent(components.Enemy)
+	if !slices.Equal(enemies, []ecs.Entity{enemyEnt}) {
+		t.Errorf("Expected only the ghost to have spawned, got %v", enemies)
+	}
+}
@@ -2,101 +2,91 @@ package systems
 
 import (
 	"errors"
-	"time"
+	"reflect"
 
 	"ecstemplate/internal/game/components"
 	"ecstemplate/internal/game/events"
+	"ecstemplate/internal/game/resources"
 	"ecstemplate/pkg/ecs"
 )
 
 type TowerFactorySystem struct {
-	ComponentAccess *components.ComponentAccess
-	Templates       map[components.TowerType]ecs.Entity
+	Templates   map[components.TowerType]ecs.Entity
+	intents     *ecs.Store[components.CreateTowerIntentComponent]
+	templates   *ecs.Store[components.TowerTemplateComponent]
+	towers      *ecs.Store[components.TowerComponent]
+	projectiles *ecs.Store[components.ProjectileSpecComponent]
+	wallets     *ecs.Store[components.WalletComponent]
 }
 
 func NewTowerFactorySystem(
 	world *ecs.World,
-	componentAccess *components.ComponentAccess,
+	towers []resources.TowerDefinition,
 ) *TowerFactorySystem {
 	tfs := &TowerFactorySystem{
-		ComponentAccess: componentAccess,
+		intents:     ecs.NewStore[components.CreateTowerIntentComponent](world),
+		templates:   ecs.NewStore[components.TowerTemplateComponent](world),
+		towers:      ecs.NewStore[components.TowerComponent](world),
+		projectiles: ecs.NewStore[components.ProjectileSpecComponent](world),
+		wallets:     ecs.NewStore[components.WalletComponent](world),
 	}
-	tfs.Initialize(world)
+	tfs.Initialize(world, towers)
 	return tfs
 }
 
-func (s *TowerFactorySystem) Initialize(world *ecs.World) {
+// Initialize creates a template entity for each type of tower
+func (s *TowerFactorySystem) Initialize(world *ecs.World, towers []resources.TowerDefinition) {
 	s.Templates = make(map[components.TowerType]ecs.Entity)
 
-	s.Templates[components.BasicTower] = world.EntityManager.CreateEntity()
-	world.ComponentManager.AddComponent(
-		s.Templates[components.BasicTower],
-		components.TowerTemplate,
-		&components.TowerTemplateComponent{
-			Type: components.BasicTower,
-			Cost: 5,
-		},
-	)
-	world.ComponentManager.AddComponent(
-		s.Templates[components.BasicTower],
-		components.Tower,
-		&components.TowerComponent{
-			Cooldown:  time.Second,
-			LastFired: time.Now(),
-			Damage:    1,
-			Range:     5,
-		},
-	)
-
-	s.Templates[components.MediumTower] = world.EntityManager.CreateEntity()
-	world.ComponentManager.AddComponent(
-		s.Templates[components.MediumTower],
-		components.TowerTemplate,
-		&components.TowerTemplateComponent{
-			Type: components.MediumTower,
-			Cost: 10,
-		},
-	)
-	world.ComponentManager.AddComponent(
-		s.Templates[components.MediumTower],
-		components.Tower,
-		&components.TowerComponent{
-			Cooldown:  time.Second / 2,
-			LastFired: time.Now(),
-			Damage:    2,
-			Range:     7,
-		},
-	)
+	for _, tower := range towers {
+		templateEnt := world.EntityManager.CreateEntity()
+		world.ComponentManager.AddComponent(
+			templateEnt,
+			components.TowerTemplate,
+			&components.TowerTemplateComponent{
+				Type:     tower.Type,
+				Name:     tower.Name,
+				Cost:     tower.Cost,
+				Symbol:   tower.Symbol,
+				Color:    tower.Color,
+				Upgrades: tower.Upgrades,
+			},
+		)
+		world.ComponentManager.AddComponent(
+			templateEnt,
+			components.Tower,
+			&components.TowerComponent{
+				Type:     tower.Type,
+				Cooldown: tower.Cooldown,
+				Range:    tower.Range,
+			},
+		)
+		projectile := tower.Projectile
+		world.ComponentManager.AddComponent(templateEnt, components.ProjectileSpec, &projectile)
+		s.Templates[tower.Type] = templateEnt
+	}
+}
 
-	s.Templates[components.HeavyTower] = world.EntityManager.CreateEntity()
-	world.ComponentManager.AddComponent(
-		s.Templates[components.HeavyTower],
-		components.TowerTemplate,
-		&components.TowerTemplateComponent{
-			Type: components.HeavyTower,
-			Cost: 15,
+func (s *TowerFactorySystem) Access() ecs.SystemAccess {
+	return ecs.SystemAccess{
+		Reads: []ecs.ComponentType{
+			components.CreateTowerIntent,
+			components.TowerTemplate,
+			components.Tower,
+			components.ProjectileSpec,
 		},
-	)
-	world.ComponentManager.AddComponent(
-		s.Templates[components.HeavyTower],
-		components.Tower,
-		&components.TowerComponent{
-			Cooldown:  time.Second / 4,
-			LastFired: time.Now(),
-			Damage:    3,
-			Range:     10,
+		Writes: []ecs.ComponentType{components.Wallet},
+		ResourceReads: []reflect.Type{
+			ecs.ResourceOf[resources.Player](),
+			ecs.ResourceOf[ecs.Clock](),
 		},
-	)
+	}
 }
 
 func (s *TowerFactorySystem) Update(world *ecs.World, deltaTime float64) {
-	// Get the player entity
-	playerEnts := world.ComponentManager.GetAllEntitiesWithComponent(components.Player)
-	if len(playerEnts) != 1 {
-		return
-	}
-	playerEnt := playerEnts[0]
-	wallet, _ := s.ComponentAccess.GetWalletComponent(playerEnt)
+	// Get the player's wallet
+	player := ecs.MustGetResource[resources.Player](world)
+	wallet, _ := s.wallets.Get(player.Entity)
 
 	// Get all CreateTowerIntent components
 	createTowerIntentEnts := world.ComponentManager.GetAllEntitiesWithComponent(
@@ -104,21 +94,26 @@ func (s *TowerFactorySystem) Update(world *ecs.World, deltaTime float64) {
 	)
 	for _, createTowerIntentEnt := range createTowerIntentEnts {
 		// Get the CreateTowerIntent component
-		createTowerIntent, _ := s.ComponentAccess.GetCreateTowerIntentComponent(
-			createTowerIntentEnt,
-		)
+		createTowerIntent, _ := s.intents.Get(createTowerIntentEnt)
+
+		// Drop intents for towers there are no templates for
+		templateEnt, found := s.Templates[createTowerIntent.TowerType]
+		if !found {
+			world.Commands().RemoveComponent(
+				createTowerIntentEnt,
+				components.CreateTowerIntent,
+			)
+			continue
+		}
 
 		// Check if the player has enough money
-		towerTemplate, _ := s.ComponentAccess.GetTowerTemplateComponent(
-			s.Templates[createTowerIntent.TowerType],
-		)
-
+		towerTemplate, _ := s.templates.Get(templateEnt)
 		if wallet.Money < towerTemplate.Cost {
 			continue
 		}
 
 		// Create the tower at the specified position
-		newTowerEnt, err := s.createTower(
+		newTowerEnt, err := s.CreateTower(
 			world,
 			createTowerIntent.TowerType,
 			createTowerIntent.Position,
@@ -129,53 +124,67 @@ func (s *TowerFactorySystem) Update(world *ecs.World, deltaTime float64) {
 
 		// Deduct the cost of the tower from the player's wallet
 		wallet.Money -= towerTemplate.Cost
+		s.wallets.MarkChanged(player.Entity)
 
-		// Queue the new tower created event
-		world.QueueEvent(&events.TowerCreatedEvent{
+		// Send the new tower created event
+		ecs.Send(world, events.TowerCreatedEvent{
 			TowerType:   createTowerIntent.TowerType,
 			TowerEntity: newTowerEnt,
 		})
 
 		// Remove the CreateTowerIntent component
-		world.ComponentManager.RemoveComponent(
+		world.Commands().RemoveComponent(
 			createTowerIntentEnt,
 			components.CreateTowerIntent,
 		)
 	}
 }
 
-func (s *TowerFactorySystem) createTower(
+// CreateTower queues a tower of the given type at the position, built from its
+// template like the ones the player buys, but for free. It's created when the
+// world's commands are applied.
+func (s *TowerFactorySystem) CreateTower(
 	world *ecs.World,
 	towerType components.TowerType,
 	position components.PositionComponent,
 ) (ecs.Entity, error) {
 	towerTemplateEnt, found := s.Templates[towerType]
 	if !found {
-		return -1, errors.New("tower type not found")
+		return ecs.NoEntity, errors.New("tower type not found")
 	}
-	towerComp, _ := s.ComponentAccess.GetTowerComponent(towerTemplateEnt)
+	towerComp, _ := s.towers.Get(towerTemplateEnt)
+	towerTemplate, _ := s.templates.Get(towerTemplateEnt)
+	projectile, _ := s.projectiles.Get(towerTemplateEnt)
 
-	tower := world.EntityManager.CreateEntity()
-	world.ComponentManager.AddComponent(
+	commands := world.Commands()
+	tower := commands.CreateEntity()
+	commands.AddComponent(
 		tower,
 		components.Tower,
 		&components.TowerComponent{
+			Type:      towerType,
 			Cooldown:  towerComp.Cooldown,
-			LastFired: time.Now(),
-			Damage:    towerComp.Damage,
+			LastFired: ecs.MustGetResource[ecs.Clock](world).Elapsed,
 			Range:     towerComp.Range,
 		},
 	)
-	world.ComponentManager.AddComponent(
+	spec := *projectile
+	commands.AddComponent(
+		tower,
+		components.ProjectileSpec,
+		&spec,
+	)
+	commands.AddComponent(
 		tower,
 		components.Position,
 		&position,
 	)
-	world.ComponentManager.AddComponent(
+	commands.AddComponent(
 		tower,
 		components.Renderable,
 		&components.RenderableComponent{
-			Symbol: "T",
+			Symbol: towerTemplate.Symbol,
+			Color:  towerTemplate.Color,
 		},
 	)
 
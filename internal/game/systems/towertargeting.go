@@ -1,7 +1,7 @@
 package systems
 
 import (
-	"time"
+	"reflect"
 
 	"ecstemplate/internal/game/components"
 	"ecstemplate/internal/game/events"
@@ -11,33 +11,70 @@ import (
 // TowerTargetingSystem is a system that monitors the closest enemy to each tower
 // and adds a shoot intent to the tower when the wait duration has passed and a target is in range
 type TowerTargetingSystem struct {
-	ComponentAccess *components.ComponentAccess
+	ready     *ecs.Query
+	targets   *ecs.Query
+	towers    *ecs.Store[components.TowerComponent]
+	positions *ecs.Store[components.PositionComponent]
 }
 
-func (s *TowerTargetingSystem) Update(world *ecs.World, deltaTime float64) {
-	// Get all towerEnts active in the world
-	towerEnts := world.ComponentManager.GetAllEntitiesWithComponents(
-		[]ecs.ComponentType{components.Tower, components.Position, components.Renderable},
-	)
+func NewTowerTargetingSystem(world *ecs.World) *TowerTargetingSystem {
+	return &TowerTargetingSystem{
+		// Towers that already have a shot queued wait for it to be fired
+		ready: world.ComponentManager.NewQueryWithFilter(
+			ecs.QueryFilter{
+				With: []ecs.ComponentType{
+					components.Tower,
+					components.Position,
+					components.Renderable,
+				},
+				Without: []ecs.ComponentType{components.ShootIntent},
+			},
+		),
+		targets: world.ComponentManager.NewQuery(
+			[]ecs.ComponentType{
+				components.Enemy,
+				components.Position,
+				components.Health,
+				components.PathFollow,
+				components.Renderable,
+			},
+		),
+		towers:    ecs.NewStore[components.TowerComponent](world),
+		positions: ecs.NewStore[components.PositionComponent](world),
+	}
+}
 
-	// Get all enemyEnts active in the world
-	enemyEnts := world.ComponentManager.GetAllEntitiesWithComponents(
-		[]ecs.ComponentType{
-			components.Enemy,
+func (s *TowerTargetingSystem) Access() ecs.SystemAccess {
+	return ecs.SystemAccess{
+		Reads: []ecs.ComponentType{
 			components.Position,
+			components.Renderable,
+			components.Enemy,
 			components.Health,
 			components.PathFollow,
-			components.Renderable,
+			components.ShootIntent,
 		},
-	)
+		Writes:        []ecs.ComponentType{components.Tower},
+		ResourceReads: []reflect.Type{ecs.ResourceOf[ecs.Clock]()},
+	}
+}
+
+func (s *TowerTargetingSystem) Update(world *ecs.World, deltaTime float64) {
+	// Get all towerEnts active in the world
+	towerEnts := s.ready.Entities()
+
+	// Get all enemyEnts active in the world
+	enemyEnts := s.targets.Entities()
+
+	clock := ecs.MustGetResource[ecs.Clock](world)
 
 	// Loop through all towers
 	for _, towerEnt := range towerEnts {
 		// Get the tower
-		tower, _ := s.ComponentAccess.GetTowerComponent(towerEnt)
+		tower, _ := s.towers.Get(towerEnt)
 
 		// Get tower position
-		towerPos, _ := s.ComponentAccess.GetPositionComponent(towerEnt)
+		towerPos, _ := s.positions.Get(towerEnt)
 
 		// Get the closest enemy to the tower
 		closestEnemyEnt, found := s.getClosestEnemy(towerPos, tower.Range, enemyEnts)
@@ -47,9 +84,10 @@ func (s *TowerTargetingSystem) Update(world *ecs.World, deltaTime float64) {
 		}
 
 		// If the tower has no cooldown, add a shoot intent
-		if time.Since(tower.LastFired) >= tower.Cooldown {
-			// Add a shoot intent to the tower
-			world.ComponentManager.AddComponent(
+		if clock.Since(tower.LastFired) >= tower.Cooldown {
+			// Add a shoot intent to the tower. It's deferred, since it takes the
+			// tower out of the query we're looping over.
+			world.Commands().AddComponent(
 				towerEnt,
 				components.ShootIntent,
 				&components.ShootIntentComponent{
@@ -58,14 +96,15 @@ func (s *TowerTargetingSystem) Update(world *ecs.World, deltaTime float64) {
 				},
 			)
 
-			// Queue the tower shot event
-			world.QueueEvent(&events.ProjectileFiredEvent{
+			// Send the tower shot event
+			ecs.Send(world, events.ProjectileFiredEvent{
 				Shooter: towerEnt,
 				Target:  closestEnemyEnt,
 			})
 
 			// Reset the last fired time
-			tower.LastFired = time.Now()
+			tower.LastFired = clock.Elapsed
+			s.towers.MarkChanged(towerEnt)
 		}
 	}
 }
@@ -75,15 +114,15 @@ func (s *TowerTargetingSystem) getClosestEnemy(
 	towerRange float64,
 	enemyEnts []ecs.Entity,
 ) (closest ecs.Entity, found bool) {
-	closest = -1
+	closest = ecs.NoEntity
 	closestDist := 999999.0
 
 	if len(enemyEnts) == 0 {
-		return -1, false
+		return ecs.NoEntity, false
 	}
 
 	for _, enemyEnt := range enemyEnts {
-		enemyPos, _ := s.ComponentAccess.GetPositionComponent(enemyEnt)
+		enemyPos, _ := s.positions.Get(enemyEnt)
 		dist := distance(*towerPos, *enemyPos)
 		if dist < closestDist && dist <= towerRange {
 			closestDist = dist
@@ -91,8 +130,8 @@ func (s *TowerTargetingSystem) getClosestEnemy(
 		}
 	}
 
-	if closest == -1 {
-		return -1, false
+	if closest == ecs.NoEntity {
+		return ecs.NoEntity, false
 	}
 
 	return closest, true
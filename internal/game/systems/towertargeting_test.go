@@ -0,0 +1,59 @@
+package systems
+
+import (
+	"log"
+	"testing"
+	"time"
+
+	"ecstemplate/internal/game/components"
+	"ecstemplate/pkg/ecs"
+)
+
+func TestTowerTargetingSystemCooldown(t *testing.T) {
+	logger := log.New(log.Writer(), "TestTowerTargetingSystemCooldown: ", log.Flags())
+	world := ecs.NewWorld(logger)
+	components.Register(world)
+
+	// A tower that fires once a second
+	towerEnt := world.EntityManager.CreateEntity()
+	tower := &components.TowerComponent{
+		Cooldown: time.Second,
+		Range:    10,
+	}
+	world.ComponentManager.AddComponent(towerEnt, components.Tower, tower)
+	world.ComponentManager.AddComponent(towerEnt, components.Position,
+		&components.PositionComponent{X: 0, Y: 0})
+	world.ComponentManager.AddComponent(towerEnt, components.Renderable,
+		&components.RenderableComponent{Symbol: "T"})
+
+	// An enemy standing in range
+	enemyEnt := world.EntityManager.CreateEntity()
+	world.ComponentManager.AddComponent(enemyEnt, components.Enemy,
+		&components.EnemyComponent{Type: "basic"})
+	world.ComponentManager.AddComponent(enemyEnt, components.Position,
+		&components.PositionComponent{X: 3, Y: 0})
+	world.ComponentManager.AddComponent(enemyEnt, components.Health,
+		&components.HealthComponent{Current: 10, Max: 10})
+	world.ComponentManager.AddComponent(enemyEnt, components.PathFollow,
+		&components.PathFollowComponent{})
+	world.ComponentManager.AddComponent(enemyEnt, components.Renderable,
+		&components.RenderableComponent{Symbol: "E"})
+
+	system := NewTowerTargetingSystem(world)
+
+	// However long the test takes, the tower only fires once a simulated second has passed
+	RunSimulation(system, world, 0.5, 60.0)
+	if world.ComponentManager.HasComponent(towerEnt, components.ShootIntent) {
+		t.Fatalf("Expected the tower not to fire before its cooldown")
+	}
+
+	RunSimulation(system, world, 0.6, 60.0)
+	if !world.ComponentManager.HasComponent(towerEnt, components.ShootIntent) {
+		t.Fatalf("Expected the tower to fire once its cooldown passed")
+	}
+
+	clock := ecs.MustGetResource[ecs.Clock](world)
+	if tower.LastFired <= time.Second || tower.LastFired > clock.Elapsed {
+		t.Errorf("Expected the tower to have fired at %v, between 1s and now", tower.LastFired)
+	}
+}
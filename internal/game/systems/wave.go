@@ -1,94 +1,158 @@
 package systems
 
 import (
-	"time"
+	"reflect"
 
 	"ecstemplate/internal/game/components"
+	"ecstemplate/internal/game/events"
+	"ecstemplate/internal/game/resources"
 	"ecstemplate/pkg/ecs"
 )
 
-// WaveSystem handles the spawning of enemies when the wave timer has passed
+// WaveSystem runs the waves in the Waves resource one after the other. Each wave is
+// built for, its groups are spawned, and it is complete once its enemies are gone.
+// Where it's up to is kept in the Wave resource, so it's saved with the world.
+//
+// The player can call a wave before its build time is up, with a CallWaveIntent,
+// and is paid a bonus for the time skipped.
 type WaveSystem struct {
-	ComponentAccess *components.ComponentAccess
-	lastSpawnTime   time.Time
-	cooldown        time.Duration
+	enemies *ecs.Store[components.EnemyComponent]
+	wallets *ecs.Store[components.WalletComponent]
 }
 
-func NewWaveSystem(
-	componentAccess *components.ComponentAccess,
-	cooldown time.Duration,
-) *WaveSystem {
+func NewWaveSystem(world *ecs.World) *WaveSystem {
 	return &WaveSystem{
-		ComponentAccess: componentAccess,
-		cooldown:        cooldown,
-		lastSpawnTime:   time.Now(),
+		enemies: ecs.NewStore[components.EnemyComponent](world),
+		wallets: ecs.NewStore[components.WalletComponent](world),
+	}
+}
+
+func (s *WaveSystem) Access() ecs.SystemAccess {
+	return ecs.SystemAccess{
+		Reads:  []ecs.ComponentType{components.Path, components.Enemy, components.CallWaveIntent},
+		Writes: []ecs.ComponentType{components.Wallet},
+		ResourceReads: []reflect.Type{
+			ecs.ResourceOf[resources.Waves](),
+			ecs.ResourceOf[resources.Enemies](),
+			ecs.ResourceOf[resources.Paths](),
+			ecs.ResourceOf[resources.Player](),
+			ecs.ResourceOf[ecs.Clock](),
+		},
+		ResourceWrites: []reflect.Type{ecs.ResourceOf[resources.Wave]()},
 	}
 }
 
 func (s *WaveSystem) Update(world *ecs.World, deltaTime float64) {
-	if time.Since(s.lastSpawnTime) >= s.cooldown {
-		// Spawn a new enemy at the start of the first path
-		pathEnts := world.ComponentManager.GetAllEntitiesWithComponent(components.Path)
-		if len(pathEnts) == 0 {
+	wave := ecs.MustGetResource[resources.Wave](world)
+	waves := ecs.MustGetResource[resources.Waves](world)
+	clock := ecs.MustGetResource[ecs.Clock](world)
+
+	if wave.Phase == resources.WaveComplete || wave.Number > len(waves.Definitions) {
+		return
+	}
+	definition := waves.Definitions[wave.Number-1]
+	wave.Remaining = s.remaining(wave.Number)
+	called := s.takeCall(world)
+
+	switch wave.Phase {
+	case resources.WaveBuilding:
+		bonus := 0.0
+		if called {
+			bonus = wave.EarlyBonus(waves, clock.Elapsed)
+			s.pay(world, bonus)
+		} else if clock.Since(wave.PhaseStart) < definition.Delay {
+			return
+		}
+		wave.Phase = resources.WaveSpawning
+		wave.PhaseStart = clock.Elapsed
+		wave.Group, wave.GroupSpawned, wave.Spawned = 0, 0, 0
+		wave.LastSpawn = clock.Elapsed
+		wave.Total = 0
+		for _, group := range definition.Groups {
+			wave.Total += group.Count
+		}
+		ecs.Send(world, events.WaveStartedEvent{Number: wave.Number, Bonus: bonus})
+
+	case resources.WaveSpawning:
+		group := definition.Groups[wave.Group]
+		wait := group.Spacing
+		if wave.GroupSpawned == 0 {
+			wait = group.Delay
+		}
+		if clock.Since(wave.LastSpawn) < wait {
 			return
 		}
-		path, _ := s.ComponentAccess.GetPathComponent(pathEnts[0])
-
-		// Create the enemy entity
-		enemyEnt := world.EntityManager.CreateEntity()
-		world.ComponentManager.AddComponent(
-			enemyEnt,
-			components.Enemy,
-			&components.EnemyComponent{
-				Type:   "basic",
-				Speed:  1,
-				Reward: 10,
-			},
-		)
-		world.ComponentManager.AddComponent(
-			enemyEnt,
-			components.BoundingBox,
-			&components.BoundingBoxComponent{
-				Width:  1,
-				Height: 1,
-			},
-		)
-		world.ComponentManager.AddComponent(
-			enemyEnt,
-			components.Position,
-			&components.PositionComponent{
-				X: path.Waypoints[0].X,
-				Y: path.Waypoints[0].Y,
-			},
-		)
-		world.ComponentManager.AddComponent(
-			enemyEnt,
-			components.Health,
-			&components.HealthComponent{
-				Current: 10,
-				Max:     10,
-			},
-		)
-		world.ComponentManager.AddComponent(
-			enemyEnt,
-			components.PathFollow,
-			&components.PathFollowComponent{
-				PathID:        "starting-path",
-				WaypointIndex: 0,
-			},
-		)
-		world.ComponentManager.AddComponent(
-			enemyEnt,
-			components.Renderable,
-			&components.RenderableComponent{
-				Symbol: "E",
-			},
-		)
-
-		// Reset the last spawn time
-		s.lastSpawnTime = time.Now()
-		if s.cooldown > time.Second/2 {
-			s.cooldown -= 200 * time.Millisecond
+
+		// An enemy that can't be spawned is skipped, and no longer counts towards the wave
+		if _, err := SpawnEnemy(world, group.Enemy, group.Path, wave.Number); err != nil {
+			world.Logger.Printf("Wave %d failed to spawn: %v", wave.Number, err)
+			wave.Total--
+		} else {
+			wave.Spawned++
+			wave.Remaining++
+		}
+		wave.LastSpawn = clock.Elapsed
+		wave.GroupSpawned++
+
+		// Move on to the next group, or wait for the wave to be cleared
+		if wave.GroupSpawned == group.Count {
+			wave.Group++
+			wave.GroupSpawned = 0
+		}
+		if wave.Group == len(definition.Groups) {
+			wave.Phase = resources.WaveClearing
+			wave.PhaseStart = clock.Elapsed
 		}
+
+	case resources.WaveClearing:
+		if wave.Remaining > 0 {
+			return
+		}
+		wave.Completed++
+		ecs.Send(world, events.WaveCompletedEvent{Number: wave.Number})
+
+		if wave.Number == len(waves.Definitions) {
+			wave.Phase = resources.WaveComplete
+			return
+		}
+		wave.Number++
+		wave.Phase = resources.WaveBuilding
+		wave.PhaseStart = clock.Elapsed
+	}
+}
+
+// takeCall reports whether the player called the next wave, removing the intent.
+// Calls while a wave is already under way are dropped.
+func (s *WaveSystem) takeCall(world *ecs.World) bool {
+	player := ecs.MustGetResource[resources.Player](world)
+	if !world.ComponentManager.HasComponent(player.Entity, components.CallWaveIntent) {
+		return false
 	}
+	world.Commands().RemoveComponent(player.Entity, components.CallWaveIntent)
+	return true
+}
+
+// pay credits the player's wallet
+func (s *WaveSystem) pay(world *ecs.World, money float64) {
+	if money <= 0 {
+		return
+	}
+	player := ecs.MustGetResource[resources.Player](world)
+	wallet, found := s.wallets.Get(player.Entity)
+	if !found {
+		return
+	}
+	wallet.Money += money
+	s.wallets.MarkChanged(player.Entity)
+}
+
+// remaining counts the enemies of the wave still on the map
+func (s *WaveSystem) remaining(number int) int {
+	count := 0
+	s.enemies.Each(func(enemyEnt ecs.Entity, enemy *components.EnemyComponent) {
+		if enemy.Wave == number {
+			count++
+		}
+	})
+	return count
 }
@@ -0,0 +1,160 @@
+package systems
+
+import (
+	"log"
+	"slices"
+	"testing"
+	"time"
+
+	"ecstemplate/internal/game/components"
+	"ecstemplate/internal/game/events"
+	"ecstemplate/internal/game/resources"
+	"ecstemplate/pkg/ecs"
+)
+
+// newWaveTestWorld creates a world with a path called "path", basic and fast enemies,
+// and a player, that plays the given waves
+func newWaveTestWorld(
+	t *testing.T,
+	definitions []resources.WaveDefinition,
+) (*ecs.World, *resources.Wave, ecs.Entity) {
+	logger := log.New(log.Writer(), t.Name()+": ", log.Flags())
+	world := ecs.NewWorld(logger)
+	components.Register(world)
+	resources.TrackPaths(world)
+
+	pathEnt := world.EntityManager.CreateEntity()
+	world.ComponentManager.AddComponent(pathEnt, components.Path, &components.PathComponent{
+		ID:        "path",
+		Waypoints: []components.PositionComponent{{X: 0, Y: 0}, {X: 10, Y: 0}},
+	})
+
+	ecs.InsertResource(world, &resources.Enemies{Archetypes: map[string]resources.EnemyArchetype{
+		"basic": {Type: "basic", Speed: 1, Health: 10, Width: 1, Height: 1, Symbol: "E"},
+		"fast":  {Type: "fast", Speed: 2, Health: 5, Width: 1, Height: 1, Symbol: "F"},
+	}})
+	ecs.InsertResource(world, &resources.Waves{Definitions: definitions})
+	wave := &resources.Wave{Number: 1, Phase: resources.WaveBuilding}
+	ecs.InsertResource(world, wave)
+
+	playerEnt := world.EntityManager.CreateEntity()
+	world.ComponentManager.AddComponent(playerEnt, components.Wallet,
+		&components.WalletComponent{})
+	ecs.InsertResource(world, &resources.Player{Entity: playerEnt})
+	return world, wave, playerEnt
+}
+
+func TestWaveSystemPhases(t *testing.T) {
+	// Two basic enemies half a second apart, then a fast one in the second wave
+	world, wave, playerEnt := newWaveTestWorld(t, []resources.WaveDefinition{
+		{
+			Delay: time.Second,
+			Groups: []resources.SpawnGroup{
+				{Enemy: "basic", Count: 2, Spacing: time.Second / 2, Path: "path"},
+			},
+		},
+		{
+			Delay:  time.Second,
+			Groups: []resources.SpawnGroup{{Enemy: "fast", Count: 1, Path: "path"}},
+		},
+	})
+	componentAccess := components.NewComponentAccess(world)
+	wallet, _ := componentAccess.GetWalletComponent(playerEnt)
+
+	started := ecs.NewEventReader[events.WaveStartedEvent](world)
+	completed := ecs.NewEventReader[events.WaveCompletedEvent](world)
+	system := NewWaveSystem(world)
+	enemies := func() []ecs.Entity {
+		return world.ComponentManager.GetAllEntitiesWithComponent(components.Enemy)
+	}
+
+	RunSimulation(system, world, 0.9, 60.0)
+	if wave.Phase != resources.WaveBuilding || len(enemies()) != 0 {
+		t.Fatalf("Expected nothing to spawn while building, got %s and %d enemies",
+			wave.Phase, len(enemies()))
+	}
+
+	RunSimulation(system, world, 0.2, 60.0)
+	if wave.Phase != resources.WaveSpawning || len(enemies()) != 1 {
+		t.Fatalf("Expected the first enemy to spawn, got %s and %d enemies",
+			wave.Phase, len(enemies()))
+	}
+	if got := started.Read(); !slices.Equal(got, []events.WaveStartedEvent{{Number: 1}}) {
+		t.Errorf("Expected wave 1 to have started, got %v", got)
+	}
+
+	RunSimulation(system, world, 0.5, 60.0)
+	if wave.Phase != resources.WaveClearing || len(enemies()) != 2 {
+		t.Fatalf("Expected the wave to be spawned, got %s and %d enemies",
+			wave.Phase, len(enemies()))
+	}
+
+	// Kill one enemy, then the other
+	world.RemoveEntity(enemies()[0])
+	RunSimulation(system, world, 0.1, 60.0)
+	if wave.Progress() != 0.5 {
+		t.Errorf("Expected the wave to be half done, got %v", wave.Progress())
+	}
+	world.RemoveEntity(enemies()[0])
+	RunSimulation(system, world, 0.1, 60.0)
+
+	if wave.Phase != resources.WaveBuilding || wave.Number != 2 || wave.Completed != 1 {
+		t.Fatalf("Expected to be building for wave 2, got %+v", wave)
+	}
+	if got := completed.Read(); !slices.Equal(got, []events.WaveCompletedEvent{{Number: 1}}) {
+		t.Errorf("Expected wave 1 to have completed, got %v", got)
+	}
+
+	// Calling the second wave straight away skips almost all of its second of build
+	// time, for a bonus of 1
+	world.ComponentManager.AddComponent(playerEnt, components.CallWaveIntent,
+		&components.CallWaveIntentComponent{})
+	RunSimulation(system, world, 0.02, 60.0)
+	want := []events.WaveStartedEvent{{Number: 2, Bonus: 1}}
+	if got := started.Read(); !slices.Equal(got, want) || wallet.Money != 1 {
+		t.Errorf("Expected wave 2 to be called early for 1, got %v and %g money",
+			got, wallet.Money)
+	}
+	if world.ComponentManager.HasComponent(playerEnt, components.CallWaveIntent) {
+		t.Errorf("Expected the call to have been taken")
+	}
+
+	// The last wave completes the run
+	RunSimulation(system, world, 0.1, 60.0)
+	enemy, _ := componentAccess.GetEnemyComponent(enemies()[0])
+	if enemy.Type != "fast" || enemy.Wave != 2 {
+		t.Errorf("Expected a fast enemy from wave 2, got %+v", enemy)
+	}
+	world.RemoveEntity(enemies()[0])
+	RunSimulation(system, world, 0.1, 60.0)
+	if wave.Phase != resources.WaveComplete || wave.Progress() != 1 {
+		t.Errorf("Expected every wave to be complete, got %+v", wave)
+	}
+}
+
+func TestWaveSystemSkipsFailedSpawns(t *testing.T) {
+	// The first group's enemies don't exist, so only the basic one ever spawns
+	world, wave, _ := newWaveTestWorld(t, []resources.WaveDefinition{{
+		Groups: []resources.SpawnGroup{
+			{Enemy: "ghost", Count: 2, Path: "path"},
+			{Enemy: "basic", Count: 1, Path: "path"},
+		},
+	}})
+	system := NewWaveSystem(world)
+
+	RunSimulation(system, world, 0.1, 60.0)
+	enemies := world.ComponentManager.GetAllEntitiesWithComponent(components.Enemy)
+	if wave.Phase != resources.WaveClearing || len(enemies) != 1 {
+		t.Fatalf("Expected only the basic enemy to spawn, got %s and %d enemies",
+			wave.Phase, len(enemies))
+	}
+	if wave.Total != 1 || wave.Spawned != 1 || wave.Remaining != 1 || wave.Progress() != 0 {
+		t.Errorf("Expected the wave to count only the basic enemy, got %+v", wave)
+	}
+
+	world.RemoveEntity(enemies[0])
+	RunSimulation(system, world, 0.1, 60.0)
+	if wave.Phase != resources.WaveComplete {
+		t.Errorf("Expected the wave to complete once the basic enemy is gone, got %+v", wave)
+	}
+}
@@ -0,0 +1,35 @@
+package headless
+
+import (
+	"ecstemplate/internal/display"
+	"ecstemplate/internal/game/components"
+	"ecstemplate/pkg/ecs"
+)
+
+// DisplayManager draws nothing, for running the game without a screen
+type DisplayManager struct{}
+
+func (dm *DisplayManager) Initialize(width, height int) error {
+	return nil
+}
+
+func (dm *DisplayManager) Clear() {}
+
+func (dm *DisplayManager) Render(
+	world *ecs.World,
+	componentAccess *components.ComponentAccess,
+) {
+}
+
+func (dm *DisplayManager) RenderEntity(
+	entity ecs.Entity,
+	position *components.PositionComponent,
+	renderable *components.RenderableComponent,
+) {
+}
+
+func (dm *DisplayManager) RenderUI(gameInfo display.GameInfo) {}
+
+func (dm *DisplayManager) Update() {}
+
+func (dm *DisplayManager) Shutdown() {}
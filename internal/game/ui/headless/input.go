@@ -0,0 +1,40 @@
+package headless
+
+import (
+	"ecstemplate/internal/game/components"
+	"ecstemplate/internal/input"
+	"ecstemplate/pkg/ecs"
+)
+
+// InputManager never presses anything, for running the game without a player
+type InputManager struct {
+	state input.InputState
+}
+
+func (im *InputManager) Initialize() error {
+	im.state = input.NewInputState()
+	return nil
+}
+
+func (im *InputManager) Update() {
+	im.state.Reset()
+}
+
+func (im *InputManager) GetState() input.InputState {
+	return im.state
+}
+
+func (im *InputManager) ProcessInputs(
+	world *ecs.World,
+	componentAccess *components.ComponentAccess,
+) {
+	// no-op, nothing is ever pressed
+}
+
+func (im *InputManager) SetCursorBounds(minX, minY, maxX, maxY int) {
+	// no-op, the cursor never moves
+}
+
+func (im *InputManager) Shutdown() {
+	// no-op, nothing to clean up
+}
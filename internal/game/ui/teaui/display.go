@@ -8,6 +8,7 @@ import (
 
 	"ecstemplate/internal/display"
 	"ecstemplate/internal/game/components"
+	"ecstemplate/internal/game/resources"
 	"ecstemplate/pkg/ecs"
 )
 
@@ -38,7 +39,11 @@ func (b Buffer) String() string {
 }
 
 type DisplayManager struct {
-	buffer *Buffer
+	buffer      *Buffer
+	paths       *ecs.Query
+	renderables *ecs.Query
+	view        string // The rendered buffer, kept until the next Update
+	viewValid   bool
 }
 
 func (dm *DisplayManager) Initialize(width, height int) error {
@@ -67,14 +72,17 @@ func (dm *DisplayManager) Render(
 	world *ecs.World,
 	componentAccess *components.ComponentAccess,
 ) {
-	// Render the path points for now
-	paths := world.ComponentManager.GetAllEntitiesWithComponents(
-		[]ecs.ComponentType{
-			components.Path,
-		},
-	)
+	// The queries are declared on the first render, since that's when we get the world
+	if dm.paths == nil {
+		dm.paths = world.ComponentManager.NewQuery([]ecs.ComponentType{components.Path})
+		dm.renderables = world.ComponentManager.NewQueryWithFilter(ecs.QueryFilter{
+			With:     []ecs.ComponentType{components.Renderable, components.Position},
+			Optional: []ecs.ComponentType{components.PreviousPosition},
+		})
+	}
 
-	for _, path := range paths {
+	// Render the path points for now
+	for _, path := range dm.paths.Entities() {
 		pathComp, _ := componentAccess.GetPathComponent(path)
 		for _, point := range pathComp.Waypoints {
 			x := int(math.Round(point.X))
@@ -92,25 +100,31 @@ func (dm *DisplayManager) Render(
 		}
 	}
 
-	// Render the entities that have rendering and a position
-	renderables := world.ComponentManager.GetAllEntitiesWithComponents(
-		[]ecs.ComponentType{
-			components.Renderable,
-			components.Position,
-		},
-	)
-
-	for _, renderable := range renderables {
+	// Render the entities that have rendering and a position. Moving entities are
+	// drawn between where they were and where they are, by how far into the next
+	// simulation step we are.
+	alpha := 1.0
+	if interpolation, found := ecs.GetResource[resources.Interpolation](world); found {
+		alpha = interpolation.Alpha
+	}
+	for _, renderable := range dm.renderables.Entities() {
 		rend, _ := componentAccess.GetRenderableComponent(renderable)
 		pos, _ := componentAccess.GetPositionComponent(renderable)
+		prev, found := ecs.GetOptional[components.PreviousPositionComponent](
+			dm.renderables, renderable,
+		)
+		if found {
+			pos = &components.PositionComponent{
+				X: prev.X + (pos.X-prev.X)*alpha,
+				Y: prev.Y + (pos.Y-prev.Y)*alpha,
+			}
+		}
 		dm.RenderEntity(renderable, pos, rend)
 	}
 
 	// Render the cursor
-	cursorEnts := world.ComponentManager.GetAllEntitiesWithComponent(components.Cursor)
-	if len(cursorEnts) == 1 {
-		cursorEnt := cursorEnts[0]
-		cursorPos, _ := componentAccess.GetPositionComponent(cursorEnt)
+	if cursor, found := ecs.GetResource[resources.Cursor](world); found {
+		cursorPos, _ := componentAccess.GetPositionComponent(cursor.Entity)
 		x := int(math.Round(cursorPos.X))
 		y := int(math.Round(cursorPos.Y))
 
@@ -136,10 +150,14 @@ func (dm *DisplayManager) RenderEntity(
 		return
 	}
 
+	color := renderable.Color
+	if color == "" {
+		color = "#CCCCCC"
+	}
 	dm.buffer.Cells[y][x] = Cell{
 		Symbol: rune(renderable.Symbol[0]),
 		BG:     lipgloss.Color("#000000"),
-		FG:     lipgloss.Color("#CCCCCC"),
+		FG:     lipgloss.Color(color),
 	}
 }
 
@@ -150,10 +168,32 @@ func (dm *DisplayManager) RenderUI(gameInfo display.GameInfo) {
 	dm.writeString(0, 2, fmt.Sprintf("Money: %0.2f", gameInfo.PlayerMoney))
 	dm.writeString(0, 3, fmt.Sprintf("Wave: %d", gameInfo.CurrentWave))
 	dm.writeString(0, 4, fmt.Sprintf("Progress: %0.2f%%", gameInfo.WaveProgress*100))
+	if gameInfo.Paused {
+		dm.writeString(0, 5, "Speed: paused")
+	} else {
+		dm.writeString(0, 5, fmt.Sprintf("Speed: %gx", gameInfo.Speed))
+	}
+	dm.writeString(0, 6, fmt.Sprintf("Seed: %d", gameInfo.Seed))
+	if gameInfo.WaveCountdown > 0 {
+		dm.writeString(0, 7, fmt.Sprintf(
+			"Next wave in %.1fs, n to call it now for %g",
+			gameInfo.WaveCountdown.Seconds(), gameInfo.EarlyBonus,
+		))
+	}
 }
 
 func (dm *DisplayManager) Update() {
-	// No-op, bubbletea reads the buffer directly
+	// bubbletea reads the buffer through View, so just drop the cached view
+	dm.viewValid = false
+}
+
+// View returns the buffer as a string, only rendering it again after an Update
+func (dm *DisplayManager) View() string {
+	if !dm.viewValid {
+		dm.view = dm.buffer.String()
+		dm.viewValid = true
+	}
+	return dm.view
 }
 
 func (dm *DisplayManager) Shutdown() {
@@ -172,10 +212,22 @@ func (dm *DisplayManager) Resize(width, height int) {
 		dm.buffer.Cells[i] = make([]Cell, width)
 	}
 	dm.Clear()
+	dm.viewValid = false
 }
 
+// writeString writes the string from x along row y, clipping whatever doesn't fit on
+// the screen
 func (dm *DisplayManager) writeString(x, y int, str string) {
-	for i, r := range str {
+	if y < 0 || y >= dm.buffer.Height {
+		return
+	}
+	for i, r := range []rune(str) {
+		if x+i < 0 {
+			continue
+		}
+		if x+i >= dm.buffer.Width {
+			return
+		}
 		dm.buffer.Cells[y][x+i] = Cell{
 			Symbol: r,
 			BG:     lipgloss.Color("#000000"),
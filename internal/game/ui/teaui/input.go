@@ -1,28 +1,75 @@
 package teaui
 
 import (
+	"fmt"
+	"maps"
+
 	"ecstemplate/internal/game/components"
+	"ecstemplate/internal/game/resources"
 	"ecstemplate/internal/input"
 	"ecstemplate/pkg/ecs"
 )
 
+// keyBindings maps the keys bubbletea reports to the actions they press. The keys
+// that pick towers to build come from the tower definitions, see bindings.
+var keyBindings = map[string]input.Action{
+	"w":     input.ActionMoveUp,
+	"up":    input.ActionMoveUp,
+	"s":     input.ActionMoveDown,
+	"down":  input.ActionMoveDown,
+	"a":     input.ActionMoveLeft,
+	"left":  input.ActionMoveLeft,
+	"d":     input.ActionMoveRight,
+	"right": input.ActionMoveRight,
+	"enter": input.ActionSelect,
+	" ":     input.ActionSelect,
+	"esc":   input.ActionCancel,
+	"n":     input.ActionNextWave,
+	"p":     input.ActionTogglePause,
+	"f":     input.ActionCycleSpeed,
+	"f5":    input.ActionSave,
+	"f9":    input.ActionLoad,
+	"tab":   input.ActionNextSlot,
+	"q":     input.ActionQuit,
+}
+
 type InputManager struct {
-	state                  input.InputState
-	keysBuffer             []string
-	minX, minY, maxX, maxY int
+	state      input.InputState
+	keysBuffer []string
+	bounds     input.Bounds
+	towers     []resources.TowerDefinition
+	bindings   map[string]input.Action
+}
+
+// NewInputManager creates an input manager with keys for building the given towers
+func NewInputManager(towers []resources.TowerDefinition) *InputManager {
+	return &InputManager{towers: towers}
 }
 
 func (im *InputManager) Initialize() error {
-	im.state = input.InputState{
-		Actions:      make(map[input.Action]bool),
-		CursorX:      10,
-		CursorY:      10,
-		IsPlacing:    false,
-		PlacingTower: "",
-	}
+	im.state = input.NewInputState()
 	im.keysBuffer = make([]string, 0)
 
-	return nil
+	var err error
+	im.bindings, err = bindings(im.towers)
+	return err
+}
+
+// bindings adds a key for each tower with a hotkey to the fixed key bindings. The
+// towers' hotkeys are already known to differ from each other, see data.ParseTowers.
+func bindings(towers []resources.TowerDefinition) (map[string]input.Action, error) {
+	bindings := maps.Clone(keyBindings)
+	for _, tower := range towers {
+		if tower.Hotkey == "" {
+			continue
+		}
+		if action, found := keyBindings[tower.Hotkey]; found {
+			return nil, fmt.Errorf("tower %q has hotkey %q, which is bound to %s",
+				tower.Type, tower.Hotkey, action)
+		}
+		bindings[tower.Hotkey] = input.BuildAction(tower.Type)
+	}
+	return bindings, nil
 }
 
 func (im *InputManager) QueueKey(key string) {
@@ -31,48 +78,12 @@ func (im *InputManager) QueueKey(key string) {
 
 func (im *InputManager) Update() {
 	// Reset actions
-	for action := range im.state.Actions {
-		im.state.Actions[action] = false
-	}
+	im.state.Reset()
 
 	// Process queued keys
 	for _, key := range im.keysBuffer {
-		switch key {
-		case "w", "up":
-			im.state.Actions[input.ActionMoveUp] = true
-			im.state.CursorY = max(im.minY, im.state.CursorY-1)
-		case "s", "down":
-			im.state.Actions[input.ActionMoveDown] = true
-			im.state.CursorY = min(im.maxY, im.state.CursorY+1)
-		case "a", "left":
-			im.state.Actions[input.ActionMoveLeft] = true
-			im.state.CursorX = max(im.minX, im.state.CursorX-1)
-		case "d", "right":
-			im.state.Actions[input.ActionMoveRight] = true
-			im.state.CursorX = min(im.maxX, im.state.CursorX+1)
-		case "1":
-			im.state.Actions[input.ActionBuildBasic] = true
-			im.state.PlacingTower = components.BasicTower
-			im.state.IsPlacing = true
-		case "2":
-			im.state.Actions[input.ActionBuildMedium] = true
-			im.state.PlacingTower = components.MediumTower
-			im.state.IsPlacing = true
-		case "3":
-			im.state.Actions[input.ActionBuildHeavy] = true
-			im.state.PlacingTower = components.HeavyTower
-			im.state.IsPlacing = true
-		case "enter", " ":
-			im.state.Actions[input.ActionSelect] = true
-		case "esc":
-			im.state.Actions[input.ActionCancel] = true
-			im.state.IsPlacing = false
-		case "n":
-			im.state.Actions[input.ActionNextWave] = true
-		case "p":
-			im.state.Actions[input.ActionTogglePause] = true
-		case "q":
-			im.state.Actions[input.ActionQuit] = true
+		if action, found := im.bindings[key]; found {
+			im.state.Press(action)
 		}
 	}
 
@@ -88,86 +99,13 @@ func (im *InputManager) ProcessInputs(
 	world *ecs.World,
 	componentAccess *components.ComponentAccess,
 ) {
-	if im.state.Actions[input.ActionSelect] && im.state.IsPlacing {
-		// Create a tower at cursor position
-		posComponent := components.PositionComponent{
-			X: float64(im.state.CursorX),
-			Y: float64(im.state.CursorY),
-		}
-
-		// Create intent to build tower
-		createTowerIntent := &components.CreateTowerIntentComponent{
-			TowerType: im.state.PlacingTower,
-			Position:  posComponent,
-		}
-
-		// Find player entity to attach the intent to
-		playerEnts := world.ComponentManager.GetAllEntitiesWithComponent(components.Player)
-		if len(playerEnts) > 0 {
-			world.ComponentManager.AddComponent(
-				playerEnts[0],
-				components.CreateTowerIntent,
-				createTowerIntent,
-			)
-		}
-
-		// Reset placement mode
-		im.state.IsPlacing = false
-	}
-
-	cursorPos := im.getCursorPosComp(world, componentAccess)
-	if im.state.Actions[input.ActionMoveLeft] {
-		if cursorPos != nil {
-			cursorPos.X = max(float64(im.minX), cursorPos.X-1)
-		}
-	}
-
-	if im.state.Actions[input.ActionMoveRight] {
-		if cursorPos != nil {
-			cursorPos.X = min(float64(im.maxX), cursorPos.X+1)
-		}
-	}
-
-	if im.state.Actions[input.ActionMoveUp] {
-		if cursorPos != nil {
-			cursorPos.Y = max(float64(im.minY), cursorPos.Y-1)
-		}
-	}
-
-	if im.state.Actions[input.ActionMoveDown] {
-		if cursorPos != nil {
-			cursorPos.Y = min(float64(im.maxY), cursorPos.Y+1)
-		}
-	}
-
-	im.state.CursorX = int(cursorPos.X)
-	im.state.CursorY = int(cursorPos.Y)
+	input.ApplyActions(world, componentAccess, &im.state, im.bounds)
 }
 
 func (im *InputManager) SetCursorBounds(minX, minY, maxX, maxY int) {
-	im.minX = minX
-	im.minY = minY
-	im.maxX = maxX
-	im.maxY = maxY
+	im.bounds = input.Bounds{MinX: minX, MinY: minY, MaxX: maxX, MaxY: maxY}
 }
 
 func (im *InputManager) Shutdown() {
 	// no-op, nothing to clean up
 }
-
-func (im *InputManager) getCursorPosComp(
-	world *ecs.World,
-	componentAccess *components.ComponentAccess,
-) *components.PositionComponent {
-	// Get the cursor
-	cursorEnts := world.ComponentManager.GetAllEntitiesWithComponent(components.Cursor)
-	if len(cursorEnts) != 1 {
-		return nil
-	}
-	cursorEnt := cursorEnts[0]
-
-	// Get the cursor position
-	cursorPos, _ := componentAccess.GetPositionComponent(cursorEnt)
-
-	return cursorPos
-}
@@ -0,0 +1,116 @@
+package input
+
+import (
+	"ecstemplate/internal/game/components"
+	"ecstemplate/internal/game/resources"
+	"ecstemplate/pkg/ecs"
+)
+
+// Bounds is the area the cursor can move in
+type Bounds struct {
+	MinX, MinY, MaxX, MaxY int
+}
+
+// NewInputState returns the state input managers start from
+func NewInputState() InputState {
+	return InputState{
+		Actions:  make(map[Action]bool),
+		Pressed:  []Action{},
+		CursorX:  10,
+		CursorY:  10,
+		SaveSlot: 1,
+	}
+}
+
+// Reset clears the actions pressed in the last update
+func (s *InputState) Reset() {
+	clear(s.Actions)
+	s.Pressed = s.Pressed[:0]
+}
+
+// Press records an action pressed this update. Actions that only change the input
+// state, like picking a tower to place, take effect straight away; the ones that
+// change the world are applied by ApplyActions.
+func (s *InputState) Press(action Action) {
+	s.Actions[action] = true
+	s.Pressed = append(s.Pressed, action)
+
+	if towerType, found := action.BuildTower(); found {
+		s.PlacingTower = towerType
+		s.IsPlacing = true
+		return
+	}
+
+	switch action {
+	case ActionCancel:
+		s.IsPlacing = false
+	case ActionNextSlot:
+		s.SaveSlot = s.SaveSlot%SaveSlots + 1
+	}
+}
+
+// ApplyActions moves the cursor, places towers and calls waves for the actions
+// pressed this update, in the order they were pressed. Every input manager applies
+// actions with it, so the same actions always have the same effect on the world.
+func ApplyActions(
+	world *ecs.World,
+	componentAccess *components.ComponentAccess,
+	state *InputState,
+	bounds Bounds,
+) {
+	cursor := ecs.MustGetResource[resources.Cursor](world)
+	cursorPos, found := componentAccess.GetPositionComponent(cursor.Entity)
+	if !found {
+		return
+	}
+	startX, startY := cursorPos.X, cursorPos.Y
+
+	for _, action := range state.Pressed {
+		switch action {
+		case ActionMoveLeft:
+			cursorPos.X = max(float64(bounds.MinX), cursorPos.X-1)
+		case ActionMoveRight:
+			cursorPos.X = min(float64(bounds.MaxX), cursorPos.X+1)
+		case ActionMoveUp:
+			cursorPos.Y = max(float64(bounds.MinY), cursorPos.Y-1)
+		case ActionMoveDown:
+			cursorPos.Y = min(float64(bounds.MaxY), cursorPos.Y+1)
+		case ActionSelect:
+			if !state.IsPlacing {
+				continue
+			}
+
+			// Attach an intent to build the tower at the cursor to the player
+			player := ecs.MustGetResource[resources.Player](world)
+			world.ComponentManager.AddComponent(
+				player.Entity,
+				components.CreateTowerIntent,
+				&components.CreateTowerIntentComponent{
+					TowerType: state.PlacingTower,
+					Position: components.PositionComponent{
+						X: cursorPos.X,
+						Y: cursorPos.Y,
+					},
+				},
+			)
+
+			// Reset placement mode
+			state.IsPlacing = false
+
+		case ActionNextWave:
+			// Ask for the next wave to start early
+			player := ecs.MustGetResource[resources.Player](world)
+			world.ComponentManager.AddComponent(
+				player.Entity,
+				components.CallWaveIntent,
+				&components.CallWaveIntentComponent{},
+			)
+		}
+	}
+
+	if cursorPos.X != startX || cursorPos.Y != startY {
+		world.ComponentManager.MarkChanged(cursor.Entity, components.Position)
+	}
+	state.CursorX = int(cursorPos.X)
+	state.CursorY = int(cursorPos.Y)
+}
@@ -1,6 +1,8 @@
 package input
 
 import (
+	"strings"
+
 	"ecstemplate/internal/game/components"
 	"ecstemplate/pkg/ecs"
 )
@@ -16,22 +18,66 @@ const (
 	ActionMoveRight   Action = "move_right"
 	ActionSelect      Action = "select"
 	ActionCancel      Action = "cancel"
-	ActionBuildBasic  Action = "build_basic"
-	ActionBuildMedium Action = "build_medium"
-	ActionBuildHeavy  Action = "build_heavy"
 	ActionNextWave    Action = "next_wave"
 	ActionTogglePause Action = "toggle_pause"
+	ActionCycleSpeed  Action = "cycle_speed"
+	ActionSave        Action = "save"
+	ActionLoad        Action = "load"
+	ActionNextSlot    Action = "next_slot"
 	ActionQuit        Action = "quit"
 )
 
+// knownActions are the actions that can be pressed, other than the build actions
+var knownActions = map[Action]bool{
+	ActionMoveUp:      true,
+	ActionMoveDown:    true,
+	ActionMoveLeft:    true,
+	ActionMoveRight:   true,
+	ActionSelect:      true,
+	ActionCancel:      true,
+	ActionNextWave:    true,
+	ActionTogglePause: true,
+	ActionCycleSpeed:  true,
+	ActionSave:        true,
+	ActionLoad:        true,
+	ActionNextSlot:    true,
+	ActionQuit:        true,
+}
+
+// Known reports whether the action is one that can be pressed, including a build action
+func (a Action) Known() bool {
+	_, building := a.BuildTower()
+	return knownActions[a] || building
+}
+
+// buildPrefix starts the actions that pick a tower to place, see BuildAction
+const buildPrefix = "build_"
+
+// BuildAction returns the action that picks a type of tower to place
+func BuildAction(towerType components.TowerType) Action {
+	return Action(buildPrefix + string(towerType))
+}
+
+// BuildTower returns the type of tower the action picks to place, if it's a
+// BuildAction
+func (a Action) BuildTower() (components.TowerType, bool) {
+	towerType, found := strings.CutPrefix(string(a), buildPrefix)
+	return components.TowerType(towerType), found && towerType != ""
+}
+
 // InputState represents the current state of all inputs
 type InputState struct {
 	Actions          map[Action]bool // True if action is active
+	Pressed          []Action        // Every action pressed this update, in order
 	CursorX, CursorY int             // Position of cursor for placement
 	PlacingTower     components.TowerType
 	IsPlacing        bool
+	SaveSlot         int // Slot that save and load use, from 1 to SaveSlots
 }
 
+// SaveSlots is the number of save slots the player can pick between
+const SaveSlots = 3
+
 // InputManager is an interface that defines the methods that an input manager should implement
 type InputManager interface {
 	// Initialize sets up the input system
@@ -0,0 +1,130 @@
+package input
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+
+	"ecstemplate/internal/game/components"
+	"ecstemplate/pkg/ecs"
+)
+
+// ReplayVersion is the version of the replay format written by Replay.Write
+const ReplayVersion = 1
+
+// Replay is every action pressed during a game, with the simulation tick it was
+// pressed at. Played back from the same seed and play area, it reproduces the game.
+type Replay struct {
+	Version       int
+	Seed          uint64
+	Width, Height int // Size of the play area
+	Entries       []ReplayEntry
+}
+
+// ReplayEntry holds the actions pressed at one tick, in order
+type ReplayEntry struct {
+	Tick    uint64 // ecs.Clock Frame when the actions were applied
+	Actions []Action
+}
+
+// NewReplay starts an empty recording of a game
+func NewReplay(seed uint64, width, height int) *Replay {
+	return &Replay{
+		Version: ReplayVersion,
+		Seed:    seed,
+		Width:   width,
+		Height:  height,
+		Entries: []ReplayEntry{},
+	}
+}
+
+// Record adds the actions pressed at the tick, if there were any
+func (r *Replay) Record(tick uint64, actions []Action) {
+	if len(actions) == 0 {
+		return
+	}
+	r.Entries = append(r.Entries, ReplayEntry{Tick: tick, Actions: slices.Clone(actions)})
+}
+
+// Write encodes the replay as JSON
+func (r *Replay) Write(out io.Writer) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// ReadReplay decodes a replay written by Replay.Write
+func ReadReplay(in io.Reader) (*Replay, error) {
+	replay := &Replay{}
+	if err := json.NewDecoder(in).Decode(replay); err != nil {
+		return nil, fmt.Errorf("reading replay: %w", err)
+	}
+	if replay.Version != ReplayVersion {
+		return nil, fmt.Errorf(
+			"replay version %d is not supported, expected %d",
+			replay.Version, ReplayVersion,
+		)
+	}
+	return replay, nil
+}
+
+// PlaybackInputManager is an InputManager that presses the actions of a replay at
+// the ticks they were recorded at, instead of reading keys
+type PlaybackInputManager struct {
+	world  *ecs.World
+	replay *Replay
+	next   int // Index of the next entry to play
+	state  InputState
+	bounds Bounds
+}
+
+func NewPlaybackInputManager(world *ecs.World, replay *Replay) *PlaybackInputManager {
+	return &PlaybackInputManager{
+		world:  world,
+		replay: replay,
+	}
+}
+
+func (pm *PlaybackInputManager) Initialize() error {
+	pm.state = NewInputState()
+	pm.next = 0
+	return nil
+}
+
+// Update presses the actions recorded for the current tick
+func (pm *PlaybackInputManager) Update() {
+	pm.state.Reset()
+
+	tick := ecs.MustGetResource[ecs.Clock](pm.world).Frame
+	if pm.next < len(pm.replay.Entries) && pm.replay.Entries[pm.next].Tick <= tick {
+		for _, action := range pm.replay.Entries[pm.next].Actions {
+			pm.state.Press(action)
+		}
+		pm.next++
+	}
+}
+
+// Done reports whether every recorded action has been played
+func (pm *PlaybackInputManager) Done() bool {
+	return pm.next == len(pm.replay.Entries)
+}
+
+func (pm *PlaybackInputManager) GetState() InputState {
+	return pm.state
+}
+
+func (pm *PlaybackInputManager) ProcessInputs(
+	world *ecs.World,
+	componentAccess *components.ComponentAccess,
+) {
+	ApplyActions(world, componentAccess, &pm.state, pm.bounds)
+}
+
+func (pm *PlaybackInputManager) SetCursorBounds(minX, minY, maxX, maxY int) {
+	pm.bounds = Bounds{MinX: minX, MinY: minY, MaxX: maxX, MaxY: maxY}
+}
+
+func (pm *PlaybackInputManager) Shutdown() {
+	// no-op, nothing to clean up
+}
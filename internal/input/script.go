@@ -0,0 +1,215 @@
+package input
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"ecstemplate/internal/game/components"
+	"ecstemplate/internal/game/resources"
+	"ecstemplate/pkg/ecs"
+)
+
+// Script is a list of commands to play a game with instead of keys, for tests and
+// demos. It's written one command per line, starting with the tick to run it at:
+//
+//	# Build a basic tower at 12,8 after one second
+//	60  move 12 8
+//	60  build basic
+//	60  place
+//	600 next_wave
+//	900 pause
+//
+// The commands are move X Y, build TYPE, place, cancel, next_wave, pause, speed
+// and press ACTION, which presses any known Action. Ticks count simulation steps, see
+// ecs.Clock Frame, and must not go down. Commands on the same tick run in order.
+type Script struct {
+	Commands []ScriptCommand
+}
+
+// ScriptCommand is one line of a script
+type ScriptCommand struct {
+	Tick   uint64 // ecs.Clock Frame to run the command at
+	Action Action // Action to press, or ActionNone to move the cursor
+	X, Y   int    // Where to move the cursor to
+}
+
+// scriptActions are the script commands that press a single action
+var scriptActions = map[string]Action{
+	"place":     ActionSelect,
+	"cancel":    ActionCancel,
+	"next_wave": ActionNextWave,
+	"pause":     ActionTogglePause,
+	"speed":     ActionCycleSpeed,
+}
+
+// ParseScript reads a script in the format described on Script
+func ParseScript(in io.Reader) (*Script, error) {
+	script := &Script{}
+	scanner := bufio.NewScanner(in)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		command, err := parseScriptCommand(fields)
+		if err != nil {
+			return nil, fmt.Errorf("script line %d: %w", line, err)
+		}
+		if count := len(script.Commands); count > 0 &&
+			command.Tick < script.Commands[count-1].Tick {
+			return nil, fmt.Errorf("script line %d: tick %d is before the line above",
+				line, command.Tick)
+		}
+		script.Commands = append(script.Commands, command)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading script: %w", err)
+	}
+	return script, nil
+}
+
+func parseScriptCommand(fields []string) (ScriptCommand, error) {
+	tick, err := strconv.ParseUint(fields[0], 10, 64)
+	if err != nil {
+		return ScriptCommand{}, fmt.Errorf("%q is not a tick", fields[0])
+	}
+	if len(fields) < 2 {
+		return ScriptCommand{}, fmt.Errorf("missing a command after the tick")
+	}
+	name, args := fields[1], fields[2:]
+	command := ScriptCommand{Tick: tick, Action: ActionNone}
+
+	wantArgs := 0
+	switch name {
+	case "move":
+		wantArgs = 2
+		if len(args) == wantArgs {
+			if command.X, err = strconv.Atoi(args[0]); err != nil {
+				return ScriptCommand{}, fmt.Errorf("%q is not an X position", args[0])
+			}
+			if command.Y, err = strconv.Atoi(args[1]); err != nil {
+				return ScriptCommand{}, fmt.Errorf("%q is not a Y position", args[1])
+			}
+		}
+	case "build":
+		wantArgs = 1
+		if len(args) == wantArgs {
+			command.Action = BuildAction(components.TowerType(args[0]))
+		}
+	case "press":
+		wantArgs = 1
+		if len(args) == wantArgs {
+			command.Action = Action(args[0])
+			if !command.Action.Known() {
+				return ScriptCommand{}, fmt.Errorf("unknown action %q", args[0])
+			}
+		}
+	default:
+		action, found := scriptActions[name]
+		if !found {
+			return ScriptCommand{}, fmt.Errorf("unknown command %q", name)
+		}
+		command.Action = action
+	}
+
+	if len(args) != wantArgs {
+		return ScriptCommand{}, fmt.Errorf("%s takes %d arguments, got %d",
+			name, wantArgs, len(args))
+	}
+	return command, nil
+}
+
+// ScriptInputManager is an InputManager that runs the commands of a script at their
+// ticks, instead of reading keys
+type ScriptInputManager struct {
+	world  *ecs.World
+	script *Script
+	next   int // Index of the next command to run
+	state  InputState
+	bounds Bounds
+}
+
+func NewScriptInputManager(world *ecs.World, script *Script) *ScriptInputManager {
+	return &ScriptInputManager{
+		world:  world,
+		script: script,
+	}
+}
+
+func (sm *ScriptInputManager) Initialize() error {
+	sm.state = NewInputState()
+	sm.next = 0
+	return nil
+}
+
+// Update presses the actions for the commands due at the current tick. Moves are
+// pressed as steps of the cursor, so a recording of the game replays them.
+func (sm *ScriptInputManager) Update() {
+	sm.state.Reset()
+
+	tick := ecs.MustGetResource[ecs.Clock](sm.world).Frame
+	if sm.next == len(sm.script.Commands) || sm.script.Commands[sm.next].Tick > tick {
+		return
+	}
+
+	// Where the cursor will be after the moves pressed so far
+	cursor := ecs.MustGetResource[resources.Cursor](sm.world)
+	cursorPos, _ := components.GetComponentT[*components.PositionComponent](
+		sm.world, cursor.Entity, components.Position,
+	)
+	x, y := int(cursorPos.X), int(cursorPos.Y)
+
+	for ; sm.next < len(sm.script.Commands); sm.next++ {
+		command := sm.script.Commands[sm.next]
+		if command.Tick > tick {
+			break
+		}
+		if command.Action != ActionNone {
+			sm.state.Press(command.Action)
+			continue
+		}
+
+		targetX := min(max(command.X, sm.bounds.MinX), sm.bounds.MaxX)
+		targetY := min(max(command.Y, sm.bounds.MinY), sm.bounds.MaxY)
+		for ; x < targetX; x++ {
+			sm.state.Press(ActionMoveRight)
+		}
+		for ; x > targetX; x-- {
+			sm.state.Press(ActionMoveLeft)
+		}
+		for ; y < targetY; y++ {
+			sm.state.Press(ActionMoveDown)
+		}
+		for ; y > targetY; y-- {
+			sm.state.Press(ActionMoveUp)
+		}
+	}
+}
+
+// Done reports whether every command of the script has been run
+func (sm *ScriptInputManager) Done() bool {
+	return sm.next == len(sm.script.Commands)
+}
+
+func (sm *ScriptInputManager) GetState() InputState {
+	return sm.state
+}
+
+func (sm *ScriptInputManager) ProcessInputs(
+	world *ecs.World,
+	componentAccess *components.ComponentAccess,
+) {
+	ApplyActions(world, componentAccess, &sm.state, sm.bounds)
+}
+
+func (sm *ScriptInputManager) SetCursorBounds(minX, minY, maxX, maxY int) {
+	sm.bounds = Bounds{MinX: minX, MinY: minY, MaxX: maxX, MaxY: maxY}
+}
+
+func (sm *ScriptInputManager) Shutdown() {
+	// no-op, nothing to clean up
+}
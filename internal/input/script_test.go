@@ -0,0 +1,60 @@
+package input
+
+import (
+	"strings"
+	"testing"
+
+	"ecstemplate/internal/game/components"
+)
+
+func TestParseScript(t *testing.T) {
+	script, err := ParseScript(strings.NewReader(`
+		# Comments and blank lines are skipped
+
+		0  move 4 -2
+		0  build medium
+		12 place
+		30 press cycle_speed
+		31 press build_heavy
+	`))
+	if err != nil {
+		t.Fatalf("Expected the script to parse, got %v", err)
+	}
+
+	want := []ScriptCommand{
+		{Tick: 0, Action: ActionNone, X: 4, Y: -2},
+		{Tick: 0, Action: BuildAction(components.MediumTower)},
+		{Tick: 12, Action: ActionSelect},
+		{Tick: 30, Action: ActionCycleSpeed},
+		{Tick: 31, Action: BuildAction(components.HeavyTower)},
+	}
+	if len(script.Commands) != len(want) {
+		t.Fatalf("Expected %d commands, got %v", len(want), script.Commands)
+	}
+	for i := range want {
+		if script.Commands[i] != want[i] {
+			t.Errorf("Expected command %d to be %+v, got %+v", i, want[i], script.Commands[i])
+		}
+	}
+}
+
+func TestParseScriptErrors(t *testing.T) {
+	tests := map[string]string{
+		"soon place":       `"soon" is not a tick`,
+		"5":                "missing a command",
+		"5 jump":           `unknown command "jump"`,
+		"5 move 1":         "move takes 2 arguments, got 1",
+		"5 build":          "build takes 1 arguments, got 0",
+		"5 place\n4 place": "script line 2: tick 4 is before the line above",
+		"5 press jump":     `unknown action "jump"`,
+		"5 press build_":   `unknown action "build_"`,
+		"5 place\n6 press": "script line 2: press takes 1 arguments, got 0",
+		"\n5 press fly":    `script line 2: unknown action "fly"`,
+	}
+	for script, want := range tests {
+		_, err := ParseScript(strings.NewReader(script))
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %q to fail with %q, got %v", script, want, err)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package ecs
+
+import "reflect"
+
+// SystemAccess lists the component types and resources a system reads and writes.
+// Structural changes go through Commands and don't need to be declared.
+type SystemAccess struct {
+	Reads  []ComponentType
+	Writes []ComponentType
+
+	// Resources are identified by type, see ResourceOf
+	ResourceReads  []reflect.Type
+	ResourceWrites []reflect.Type
+}
+
+// AccessDeclarer is implemented by systems that declare their component access.
+// Only systems that declare it can run in parallel with others.
+type AccessDeclarer interface {
+	Access() SystemAccess
+}
+
+// ResourceOf returns the type a resource of type T is stored under, for declaring access to it
+func ResourceOf[T any]() reflect.Type {
+	return reflect.TypeFor[T]()
+}
+
+// ConflictsWith reports whether two systems touching these components and
+// resources at the same time could race, i.e. either one writes something the other uses
+func (a SystemAccess) ConflictsWith(other SystemAccess) bool {
+	return overlaps(a.Writes, other.Reads) || overlaps(a.Writes, other.Writes) ||
+		overlaps(other.Writes, a.Reads) ||
+		overlaps(a.ResourceWrites, other.ResourceReads) ||
+		overlaps(a.ResourceWrites, other.ResourceWrites) ||
+		overlaps(other.ResourceWrites, a.ResourceReads)
+}
+
+// resources returns every resource the access declares, read or written
+func (a SystemAccess) resources() []reflect.Type {
+	return append(append([]reflect.Type{}, a.ResourceReads...), a.ResourceWrites...)
+}
+
+func overlaps[T comparable](written, used []T) bool {
+	for _, w := range written {
+		for _, u := range used {
+			if w == u {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package ecs
+
+// Tick counts the world's change detection steps. Components remember the tick they
+// were last added or changed at, so a system can ask for what changed since it last ran.
+//
+// The tick advances before each batch of systems runs and before queued commands and
+// events are applied, so a system that records ChangeTick at the end of its run sees
+// every later change, but not its own.
+type Tick uint64
+
+// ChangeTracker is implemented by systems that look for what changed since they last
+// ran. Before each Update the schedule calls SetLastRun with the tick the system last
+// ran at, or 0 before its first run, so it can be handed to Query.ChangedSince.
+type ChangeTracker interface {
+	SetLastRun(tick Tick)
+}
+
+// ChangeTick returns the current change tick
+func (w *World) ChangeTick() Tick {
+	return w.ComponentManager.tick
+}
+
+func (cm *ComponentManager) advanceTick() {
+	cm.tick++
+}
+
+// markTick records that the entity's component changed at the current tick
+func (cm *ComponentManager) markTick(entity Entity, componentType ComponentType) {
+	cm.ticks[componentType][entity] = cm.tick
+}
+
+// ChangedSince reports whether the entity's component was added or changed after the tick
+func (cm *ComponentManager) ChangedSince(
+	entity Entity,
+	componentType ComponentType,
+	since Tick,
+) bool {
+	tick, found := cm.ticks[componentType][entity]
+	return found && tick > since
+}
+
+// ChangedSince returns the matching entities whose Changed components were added or
+// changed after the tick, e.g. the one a ChangeTracker system last ran at. The slice is
+// reused by the next call.
+//
+// Only changes made through Store.GetMut, MarkChanged, or by adding the component are
+// seen. Code that writes through a pointer from Get must call MarkChanged after.
+func (q *Query) ChangedSince(since Tick) []Entity {
+	q.changed = q.changed[:0]
+	for _, entity := range q.entities {
+		for _, componentType := range q.filter.Changed {
+			if q.cm.ChangedSince(entity, componentType, since) {
+				q.changed = append(q.changed, entity)
+				break
+			}
+		}
+	}
+	return q.changed
+}
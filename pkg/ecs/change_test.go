@@ -0,0 +1,117 @@
+package ecs
+
+import (
+	"slices"
+	"testing"
+)
+
+// moveOneSystem moves a single entity each frame it's given one
+type moveOneSystem struct {
+	positions *Store[testPosition]
+	target    Entity
+}
+
+func (s *moveOneSystem) Update(world *World, deltaTime float64) {
+	if pos, found := s.positions.GetMut(s.target); found {
+		pos.X++
+	}
+}
+
+// changeReaderSystem records which entities moved since it last ran, as told by
+// the schedule
+type changeReaderSystem struct {
+	query   *Query
+	lastRun Tick
+	seen    [][]Entity
+}
+
+func (s *changeReaderSystem) SetLastRun(tick Tick) {
+	s.lastRun = tick
+}
+
+func (s *changeReaderSystem) Update(world *World, deltaTime float64) {
+	s.seen = append(s.seen, slices.Clone(s.query.ChangedSince(s.lastRun)))
+}
+
+func TestQueryChangedSinceLastRun(t *testing.T) {
+	world := newTestWorld(t)
+	positions := NewStore[testPosition](world)
+
+	a := world.EntityManager.CreateEntity()
+	b := world.EntityManager.CreateEntity()
+	positions.Add(a, &testPosition{})
+	positions.Add(b, &testPosition{})
+
+	mover := &moveOneSystem{positions: positions, target: NoEntity}
+	reader := &changeReaderSystem{
+		query: world.ComponentManager.NewQueryWithFilter(QueryFilter{
+			With:    []ComponentType{testPositionType},
+			Changed: []ComponentType{testPositionType},
+		}),
+	}
+	world.AddSystemWithOptions(mover, SystemOptions{Phase: PhasePreUpdate})
+	world.AddSystem(reader)
+
+	// First run: both entities were added since the start
+	world.Update(1.0 / 60)
+
+	// Nothing changed
+	world.Update(1.0 / 60)
+
+	// Only b moves
+	mover.target = b
+	world.Update(1.0 / 60)
+
+	// Modifying b through Get isn't noticed, but marking a is
+	mover.target = NoEntity
+	pos, _ := positions.Get(b)
+	pos.Y = 1
+	positions.MarkChanged(a)
+	world.Update(1.0 / 60)
+
+	expected := [][]Entity{{a, b}, {}, {b}, {a}}
+	if !slices.EqualFunc(reader.seen, expected, slices.Equal) {
+		t.Errorf("Expected changed entities %v, got %v", expected, reader.seen)
+	}
+
+	// Marking a missing component doesn't record a change
+	c := world.EntityManager.CreateEntity()
+	positions.MarkChanged(c)
+	if world.ComponentManager.ChangedSince(c, testPositionType, 0) {
+		t.Errorf("Expected entity %d without a position not to be changed", c)
+	}
+}
+
+func TestChangeTrackerSeesChangesFromSkippedFrames(t *testing.T) {
+	world := newTestWorld(t)
+	positions := NewStore[testPosition](world)
+	a := world.EntityManager.CreateEntity()
+	positions.Add(a, &testPosition{})
+
+	mover := &moveOneSystem{positions: positions, target: NoEntity}
+	reader := &changeReaderSystem{
+		query: world.ComponentManager.NewQueryWithFilter(QueryFilter{
+			With:    []ComponentType{testPositionType},
+			Changed: []ComponentType{testPositionType},
+		}),
+	}
+	reading := true
+	world.AddSystemWithOptions(mover, SystemOptions{Phase: PhasePreUpdate})
+	world.AddSystemWithOptions(reader, SystemOptions{
+		RunIf: func(world *World) bool { return reading },
+	})
+	world.Update(1.0 / 60)
+
+	// a moves while the reader isn't running, and it's still seen on its next run
+	mover.target = a
+	reading = false
+	world.Update(1.0 / 60)
+	mover.target = NoEntity
+	reading = true
+	world.Update(1.0 / 60)
+
+	expected := [][]Entity{{a}, {a}}
+	if !slices.EqualFunc(reader.seen, expected, slices.Equal) {
+		t.Errorf("Expected changed entities %v, got %v", expected, reader.seen)
+	}
+}
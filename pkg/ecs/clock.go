@@ -0,0 +1,25 @@
+package ecs
+
+import "time"
+
+// Clock is the simulation time, a resource every world has. It only moves when the
+// world is updated, by the deltaTime passed to Update, so systems that time things
+// with it follow pauses, speed changes and tests rather than the wall clock.
+type Clock struct {
+	Elapsed time.Duration // Simulation time since the world was created
+	Delta   time.Duration // Time the current update covers
+	Frame   uint64        // Number of updates so far
+}
+
+// Since returns the simulation time that has passed since t
+func (c *Clock) Since(t time.Duration) time.Duration {
+	return c.Elapsed - t
+}
+
+// Advance moves the clock on by deltaTime seconds. World.Update calls it; it only
+// needs calling directly when running systems without the world's schedule.
+func (c *Clock) Advance(deltaTime float64) {
+	c.Delta = time.Duration(deltaTime * float64(time.Second))
+	c.Elapsed += c.Delta
+	c.Frame++
+}
@@ -0,0 +1,110 @@
+package ecs
+
+import "sync"
+
+type commandKind int
+
+const (
+	addComponentCommand commandKind = iota
+	removeComponentCommand
+	removeEntityCommand
+)
+
+type command struct {
+	kind          commandKind
+	entity        Entity
+	componentType ComponentType
+	component     ComponentInterface
+}
+
+// Commands buffers structural changes (creating and removing entities, adding and
+// removing components) so systems can queue them while iterating queries.
+// The world applies the buffer after every system and after events are processed.
+// Queueing is safe from systems running in parallel.
+type Commands struct {
+	world    *World
+	mu       sync.Mutex
+	commands []command
+}
+
+func NewCommands(world *World) *Commands {
+	return &Commands{
+		world:    world,
+		commands: []command{},
+	}
+}
+
+// CreateEntity reserves a new entity straight away so components can be queued for it.
+// It has no components, and so doesn't match any query, until the buffer is applied.
+func (c *Commands) CreateEntity() Entity {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.world.EntityManager.CreateEntity()
+}
+
+// AddComponent queues a component to be added to the entity
+func (c *Commands) AddComponent(
+	entity Entity,
+	componentType ComponentType,
+	component ComponentInterface,
+) {
+	c.push(command{
+		kind:          addComponentCommand,
+		entity:        entity,
+		componentType: componentType,
+		component:     component,
+	})
+}
+
+// RemoveComponent queues a component to be removed from the entity
+func (c *Commands) RemoveComponent(entity Entity, componentType ComponentType) {
+	c.push(command{
+		kind:          removeComponentCommand,
+		entity:        entity,
+		componentType: componentType,
+	})
+}
+
+// RemoveEntity queues the entity and all of its components to be removed
+func (c *Commands) RemoveEntity(entity Entity) {
+	c.push(command{
+		kind:   removeEntityCommand,
+		entity: entity,
+	})
+}
+
+func (c *Commands) push(cmd command) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.commands = append(c.commands, cmd)
+}
+
+// Len returns the number of queued commands
+func (c *Commands) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.commands)
+}
+
+// Apply runs the queued commands in the order they were queued and clears the buffer.
+// It must not be called while systems are running.
+func (c *Commands) Apply() {
+	// Index loop so commands queued while applying are run too
+	for i := 0; i < len(c.commands); i++ {
+		cmd := c.commands[i]
+		switch cmd.kind {
+		case addComponentCommand:
+			// Skip entities that were removed after the component was queued
+			if c.world.EntityManager.IsAlive(cmd.entity) {
+				c.world.ComponentManager.AddComponent(cmd.entity, cmd.componentType, cmd.component)
+			}
+		case removeComponentCommand:
+			c.world.ComponentManager.RemoveComponent(cmd.entity, cmd.componentType)
+		case removeEntityCommand:
+			c.world.RemoveEntity(cmd.entity)
+		}
+	}
+
+	clear(c.commands)
+	c.commands = c.commands[:0]
+}
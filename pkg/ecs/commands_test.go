@@ -0,0 +1,77 @@
+package ecs
+
+import "testing"
+
+// despawnSystem queues every entity with a position for removal while iterating its query
+type despawnSystem struct {
+	positions *Query
+	visited   int
+}
+
+func (s *despawnSystem) Update(world *World, deltaTime float64) {
+	for _, entity := range s.positions.Entities() {
+		s.visited++
+		world.Commands().RemoveEntity(entity)
+	}
+}
+
+// spawnCheckSystem records how many positions it can see
+type spawnCheckSystem struct {
+	positions *Query
+	seen      int
+}
+
+func (s *spawnCheckSystem) Update(world *World, deltaTime float64) {
+	s.seen = s.positions.Len()
+}
+
+func TestCommandsAreAppliedAfterEachSystem(t *testing.T) {
+	world := newTestWorld(t)
+	cm := world.ComponentManager
+
+	for range 3 {
+		cm.AddComponent(world.EntityManager.CreateEntity(), testPositionType, &testPosition{})
+	}
+
+	despawn := &despawnSystem{positions: cm.NewQuery([]ComponentType{testPositionType})}
+	check := &spawnCheckSystem{positions: cm.NewQuery([]ComponentType{testPositionType})}
+	world.AddSystem(despawn)
+	world.AddSystem(check)
+
+	// Queue an entity before the update; it appears at the first sync point
+	spawned := world.Commands().CreateEntity()
+	world.Commands().AddComponent(spawned, testPositionType, &testPosition{})
+	if cm.HasComponent(spawned, testPositionType) {
+		t.Fatalf("Expected queued component not to be added before the commands are applied")
+	}
+
+	world.Commands().Apply()
+	world.Update(1.0 / 60)
+
+	if despawn.visited != 4 {
+		t.Errorf("Expected the despawn system to visit all 4 entities, visited %d", despawn.visited)
+	}
+	if check.seen != 0 {
+		t.Errorf("Expected the next system to see no positions, saw %d", check.seen)
+	}
+	if len(world.EntityManager.GetAllEntities()) != 0 {
+		t.Errorf("Expected every entity to be removed, got %v", world.EntityManager.GetAllEntities())
+	}
+}
+
+func TestCommandsSkipComponentsForRemovedEntities(t *testing.T) {
+	world := newTestWorld(t)
+	commands := world.Commands()
+
+	entity := commands.CreateEntity()
+	commands.RemoveEntity(entity)
+	commands.AddComponent(entity, testPositionType, &testPosition{})
+	commands.Apply()
+
+	if world.ComponentManager.HasComponent(entity, testPositionType) {
+		t.Errorf("Expected no component on removed entity %d", entity)
+	}
+	if commands.Len() != 0 {
+		t.Errorf("Expected an empty buffer after Apply, got %d commands", commands.Len())
+	}
+}
@@ -18,17 +18,26 @@ type ComponentType string
 // ComponentManager handles storage and retrieval of components
 type ComponentManager struct {
 	components map[ComponentType]map[Entity]ComponentInterface
+	queries    map[ComponentType][]*Query // Queries to update when a component type changes
+	hooks      map[ComponentType]*componentHooks
+	ticks      map[ComponentType]map[Entity]Tick // When each component was last added or changed
+	tick       Tick
 }
 
 func NewComponentManager() *ComponentManager {
 	return &ComponentManager{
 		components: make(map[ComponentType]map[Entity]ComponentInterface),
+		queries:    make(map[ComponentType][]*Query),
+		hooks:      make(map[ComponentType]*componentHooks),
+		ticks:      make(map[ComponentType]map[Entity]Tick),
+		tick:       1,
 	}
 }
 
 func (cm *ComponentManager) RegisterComponentType(componentType ComponentType) {
 	if _, exists := cm.components[componentType]; !exists {
 		cm.components[componentType] = make(map[Entity]ComponentInterface)
+		cm.ticks[componentType] = make(map[Entity]Tick)
 	}
 }
 
@@ -40,12 +49,41 @@ func (cm *ComponentManager) AddComponent(
 	if _, exists := cm.components[componentType]; !exists {
 		cm.RegisterComponentType(componentType)
 	}
+
+	hooks, observed := cm.hooks[componentType]
+	if observed {
+		if old, found := cm.components[componentType][entity]; found {
+			runHooks(hooks.onRemove, entity, old)
+		}
+	}
+
 	cm.components[componentType][entity] = component
+	cm.markTick(entity, componentType)
+
+	for _, q := range cm.queries[componentType] {
+		q.update(cm, entity)
+	}
+
+	if observed {
+		runHooks(hooks.onAdd, entity, component)
+	}
 }
 
 func (cm *ComponentManager) RemoveComponent(entity Entity, componentType ComponentType) {
 	if componentMap, exists := cm.components[componentType]; exists {
+		component, found := componentMap[entity]
+		if !found {
+			return
+		}
+		if hooks, observed := cm.hooks[componentType]; observed {
+			runHooks(hooks.onRemove, entity, component)
+		}
 		delete(componentMap, entity)
+		delete(cm.ticks[componentType], entity)
+
+		for _, q := range cm.queries[componentType] {
+			q.update(cm, entity)
+		}
 	}
 }
 
@@ -115,7 +153,7 @@ func (cm *ComponentManager) GetAllEntitiesWithComponents(componentTypes []Compon
 }
 
 func (cm *ComponentManager) RemoveAllComponents(entity Entity) {
-	for _, componentMap := range cm.components {
-		delete(componentMap, entity)
+	for componentType := range cm.components {
+		cm.RemoveComponent(entity, componentType)
 	}
 }
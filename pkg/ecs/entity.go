@@ -1,41 +1,105 @@
 package ecs
 
-// Entity is just an identifier for game objects
-type Entity int
+// Entity is just an identifier for game objects.
+// The low 32 bits are an index that is recycled once the entity is removed, and the
+// bits above hold the generation of that index, so stale handles can be detected.
+type Entity int64
+
+// NoEntity is the handle used when there is no entity to refer to
+const NoEntity Entity = -1
+
+const indexBits = 32
+
+func newEntity(index, generation uint32) Entity {
+	return Entity(uint64(generation)<<indexBits | uint64(index))
+}
+
+// Index returns the slot the entity occupies in the EntityManager
+func (e Entity) Index() uint32 {
+	return uint32(e)
+}
+
+// Generation returns how many times the entity's index has been recycled
+func (e Entity) Generation() uint32 {
+	return uint32(uint64(e) >> indexBits)
+}
 
 // EntityManager handles entity creation and removal
 type EntityManager struct {
-	nextEntityID Entity
-	entities     map[Entity]struct{}
+	generations []uint32 // Current generation of each index. Index 0 is never used.
+	alive       []bool
+	free        []uint32 // Indices of removed entities, ready to be reused
+	count       int
 }
 
 func NewEntityManager() *EntityManager {
 	return &EntityManager{
-		nextEntityID: 1,
-		entities:     make(map[Entity]struct{}),
+		generations: []uint32{0},
+		alive:       []bool{false},
+		free:        []uint32{},
 	}
 }
 
 func (em *EntityManager) CreateEntity() Entity {
-	e := em.nextEntityID
-	em.entities[e] = struct{}{}
-	em.nextEntityID++
-	return e
+	em.count++
+
+	// Reuse a removed entity's index if there is one
+	if n := len(em.free); n > 0 {
+		index := em.free[n-1]
+		em.free = em.free[:n-1]
+		em.alive[index] = true
+		return newEntity(index, em.generations[index])
+	}
+
+	index := uint32(len(em.generations))
+	em.generations = append(em.generations, 0)
+	em.alive = append(em.alive, true)
+	return newEntity(index, 0)
 }
 
 func (em *EntityManager) RemoveEntity(entity Entity) {
-	delete(em.entities, entity)
+	if !em.IsAlive(entity) {
+		return
+	}
+
+	// Bumping the generation invalidates every existing handle to this index
+	index := entity.Index()
+	em.alive[index] = false
+	em.generations[index]++
+	em.free = append(em.free, index)
+	em.count--
 }
 
+// IsAlive reports whether the entity exists and hasn't been removed since the
+// handle was created
+func (em *EntityManager) IsAlive(entity Entity) bool {
+	if entity <= 0 {
+		return false
+	}
+	index := entity.Index()
+	return int(index) < len(em.generations) &&
+		em.alive[index] &&
+		em.generations[index] == entity.Generation()
+}
+
+// HasEntity reports whether the entity is alive.
+//
+// Deprecated: use IsAlive, which this calls.
 func (em *EntityManager) HasEntity(entity Entity) bool {
-	_, exists := em.entities[entity]
-	return exists
+	return em.IsAlive(entity)
+}
+
+// Len returns the number of live entities
+func (em *EntityManager) Len() int {
+	return em.count
 }
 
 func (em *EntityManager) GetAllEntities() []Entity {
-	entities := make([]Entity, 0, len(em.entities))
-	for e := range em.entities {
-		entities = append(entities, e)
+	entities := make([]Entity, 0, em.count)
+	for index, alive := range em.alive {
+		if alive {
+			entities = append(entities, newEntity(uint32(index), em.generations[index]))
+		}
 	}
 	return entities
 }
@@ -0,0 +1,56 @@
+package ecs
+
+import "testing"
+
+func TestEntityRecyclingBumpsGeneration(t *testing.T) {
+	em := NewEntityManager()
+
+	first := em.CreateEntity()
+	if first.Index() != 1 || first.Generation() != 0 {
+		t.Fatalf("Expected the first entity to be index 1 generation 0, got %d/%d",
+			first.Index(), first.Generation())
+	}
+
+	em.RemoveEntity(first)
+	if em.IsAlive(first) {
+		t.Errorf("Expected removed entity %d not to be alive", first)
+	}
+
+	// The freed index is reused with a new generation
+	second := em.CreateEntity()
+	if second.Index() != first.Index() {
+		t.Errorf("Expected index %d to be reused, got %d", first.Index(), second.Index())
+	}
+	if second.Generation() != first.Generation()+1 {
+		t.Errorf("Expected generation %d, got %d", first.Generation()+1, second.Generation())
+	}
+	if second == first {
+		t.Errorf("Expected the recycled handle to differ from the stale one")
+	}
+
+	// The stale handle stays dead and removing it doesn't touch the new entity
+	em.RemoveEntity(first)
+	if em.IsAlive(first) || !em.IsAlive(second) {
+		t.Errorf("Expected only the new handle %d to be alive", second)
+	}
+	if em.HasEntity(first) || !em.HasEntity(second) {
+		t.Errorf("Expected HasEntity to agree with IsAlive")
+	}
+	if em.Len() != 1 {
+		t.Errorf("Expected 1 live entity, got %d", em.Len())
+	}
+}
+
+func TestEntityIDsDoNotGrowWithChurn(t *testing.T) {
+	em := NewEntityManager()
+	for range 1000 {
+		em.RemoveEntity(em.CreateEntity())
+	}
+
+	if last := em.CreateEntity(); last.Index() != 1 {
+		t.Errorf("Expected churned entities to keep reusing index 1, got %d", last.Index())
+	}
+	if em.IsAlive(NoEntity) {
+		t.Errorf("Expected NoEntity never to be alive")
+	}
+}
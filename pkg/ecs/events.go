@@ -0,0 +1,167 @@
+package ecs
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Events are plain values, keyed by their Go type. They can be consumed three ways:
+//   - Trigger calls the subscribers straight away
+//   - Send queues the subscribers to be called once the frame's systems have run
+//   - EventReader lets a system poll for events from the previous and current frame
+//
+// Both Send and Trigger make the event visible to readers.
+
+// eventChannel is the part of Events[T] the world needs without knowing T
+type eventChannel interface {
+	endFrame()
+}
+
+// Events holds the buffered events and subscribers for one event type
+type Events[T any] struct {
+	mu            sync.Mutex
+	previous      []T // Events sent last frame
+	current       []T // Events sent this frame
+	previousStart uint64
+	currentStart  uint64
+	sent          uint64 // Number of events sent so far, the next event's sequence number
+	subscribers   []func(T)
+}
+
+func eventsFor[T any](world *World) *Events[T] {
+	world.eventMu.Lock()
+	defer world.eventMu.Unlock()
+
+	key := reflect.TypeFor[T]()
+	if channel, found := world.events[key]; found {
+		return channel.(*Events[T])
+	}
+
+	events := &Events[T]{}
+	world.events[key] = events
+	return events
+}
+
+// Subscribe registers a handler that is called for every event of type T
+func Subscribe[T any](world *World, handler func(T)) {
+	events := eventsFor[T](world)
+	events.mu.Lock()
+	defer events.mu.Unlock()
+	events.subscribers = append(events.subscribers, handler)
+}
+
+// Send records the event for readers and queues it for the subscribers, which are
+// called after the systems have run. It is safe to call from parallel systems.
+func Send[T any](world *World, event T) {
+	events := eventsFor[T](world)
+	events.record(event)
+
+	world.eventMu.Lock()
+	defer world.eventMu.Unlock()
+	world.pendingEvents = append(world.pendingEvents, func() {
+		events.notify(event)
+	})
+}
+
+// Trigger records the event for readers and calls the subscribers immediately
+func Trigger[T any](world *World, event T) {
+	events := eventsFor[T](world)
+	events.record(event)
+	events.notify(event)
+}
+
+func (e *Events[T]) record(event T) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.current = append(e.current, event)
+	e.sent++
+}
+
+func (e *Events[T]) notify(event T) {
+	e.mu.Lock()
+	subscribers := e.subscribers
+	e.mu.Unlock()
+
+	for _, subscriber := range subscribers {
+		subscriber(event)
+	}
+}
+
+// endFrame drops last frame's events and starts a new frame
+func (e *Events[T]) endFrame() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	clear(e.previous)
+	e.previous, e.current = e.current, e.previous[:0]
+	e.previousStart = e.currentStart
+	e.currentStart = e.sent
+}
+
+// EventReader reads the events of type T a system hasn't seen yet.
+// Events stay readable for the frame they were sent in and the one after,
+// so a system sees events sent by systems that ran after it last frame.
+type EventReader[T any] struct {
+	events *Events[T]
+	next   uint64 // Sequence number of the first unread event
+	buffer []T
+}
+
+// NewEventReader creates a reader that sees events sent from now on
+func NewEventReader[T any](world *World) *EventReader[T] {
+	events := eventsFor[T](world)
+	events.mu.Lock()
+	defer events.mu.Unlock()
+	return &EventReader[T]{
+		events: events,
+		next:   events.sent,
+	}
+}
+
+// Read returns the unread events in the order they were sent.
+// The slice is reused by the next call to Read.
+func (r *EventReader[T]) Read() []T {
+	e := r.events
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	r.buffer = r.buffer[:0]
+	if r.next < e.previousStart {
+		// The reader fell more than a frame behind; those events are gone
+		r.next = e.previousStart
+	}
+	if r.next < e.currentStart {
+		r.buffer = append(r.buffer, e.previous[r.next-e.previousStart:]...)
+		r.next = e.currentStart
+	}
+	r.buffer = append(r.buffer, e.current[r.next-e.currentStart:]...)
+	r.next = e.sent
+	return r.buffer
+}
+
+// flushEvents calls the subscribers of every sent event, including events sent
+// by the subscribers themselves, in the order they were sent
+func (w *World) flushEvents() {
+	for {
+		w.eventMu.Lock()
+		pending := w.pendingEvents
+		w.pendingEvents = nil
+		w.eventMu.Unlock()
+
+		if len(pending) == 0 {
+			return
+		}
+		for _, notify := range pending {
+			notify()
+		}
+	}
+}
+
+// endEventFrame moves every event type on to the next frame
+func (w *World) endEventFrame() {
+	w.eventMu.Lock()
+	defer w.eventMu.Unlock()
+	for _, channel := range w.events {
+		channel.endFrame()
+	}
+}
@@ -0,0 +1,90 @@
+package ecs
+
+import (
+	"slices"
+	"testing"
+)
+
+type testHitEvent struct {
+	Damage int
+}
+
+type testDeathEvent struct{}
+
+// hitSystem sends one hit per frame
+type hitSystem struct {
+	damage int
+}
+
+func (s *hitSystem) Update(world *World, deltaTime float64) {
+	s.damage++
+	Send(world, testHitEvent{Damage: s.damage})
+}
+
+// hitReaderSystem collects every hit it reads
+type hitReaderSystem struct {
+	reader *EventReader[testHitEvent]
+	seen   []int
+}
+
+func (s *hitReaderSystem) Update(world *World, deltaTime float64) {
+	for _, hit := range s.reader.Read() {
+		s.seen = append(s.seen, hit.Damage)
+	}
+}
+
+func TestEventReadersSeeEachEventOnce(t *testing.T) {
+	world := newTestWorld(t)
+
+	// One reader runs before the sender, one after, so the first only sees
+	// each hit on the frame after it was sent
+	before := &hitReaderSystem{reader: NewEventReader[testHitEvent](world)}
+	after := &hitReaderSystem{reader: NewEventReader[testHitEvent](world)}
+	world.AddSystemWithOptions(before, SystemOptions{Name: "before"})
+	world.AddSystemWithOptions(&hitSystem{}, SystemOptions{Name: "hits"})
+	world.AddSystemWithOptions(after, SystemOptions{Name: "after"})
+
+	world.Update(1.0 / 60)
+	if !slices.Equal(before.seen, []int(nil)) || !slices.Equal(after.seen, []int{1}) {
+		t.Fatalf("Expected only the later reader to see the first hit, got %v and %v",
+			before.seen, after.seen)
+	}
+
+	world.Update(1.0 / 60)
+	world.Update(1.0 / 60)
+	if !slices.Equal(before.seen, []int{1, 2}) {
+		t.Errorf("Expected the earlier reader to see hits a frame late, got %v", before.seen)
+	}
+	if !slices.Equal(after.seen, []int{1, 2, 3}) {
+		t.Errorf("Expected the later reader to see hits the same frame, got %v", after.seen)
+	}
+}
+
+func TestSendDefersSubscribersAndTriggerDoesNot(t *testing.T) {
+	world := newTestWorld(t)
+
+	hits := []int{}
+	deaths := 0
+	Subscribe(world, func(hit testHitEvent) {
+		hits = append(hits, hit.Damage)
+		if hit.Damage >= 10 {
+			// Events sent from subscribers are delivered in the same flush
+			Send(world, testDeathEvent{})
+		}
+	})
+	Subscribe(world, func(testDeathEvent) {
+		deaths++
+	})
+
+	Send(world, testHitEvent{Damage: 10})
+	Trigger(world, testHitEvent{Damage: 1})
+	if !slices.Equal(hits, []int{1}) {
+		t.Fatalf("Expected only the triggered hit before the update, got %v", hits)
+	}
+
+	world.Update(1.0 / 60)
+	if !slices.Equal(hits, []int{1, 10}) || deaths != 1 {
+		t.Errorf("Expected the sent hit and the death it caused after the update, got %v and %d deaths",
+			hits, deaths)
+	}
+}
@@ -0,0 +1,80 @@
+package ecs
+
+// ComponentHook is called with the entity and component an observer fired for
+type ComponentHook func(entity Entity, component ComponentInterface)
+
+// componentHooks are the observers registered for one component type
+type componentHooks struct {
+	onAdd    []ComponentHook
+	onRemove []ComponentHook
+	onChange []ComponentHook
+}
+
+func (cm *ComponentManager) hooksFor(componentType ComponentType) *componentHooks {
+	hooks, exists := cm.hooks[componentType]
+	if !exists {
+		hooks = &componentHooks{}
+		cm.hooks[componentType] = hooks
+	}
+	return hooks
+}
+
+// OnAdd registers a hook called after a component of the type is added to an entity.
+// Replacing a component counts as removing the old one and adding the new one.
+func (cm *ComponentManager) OnAdd(componentType ComponentType, hook ComponentHook) {
+	hooks := cm.hooksFor(componentType)
+	hooks.onAdd = append(hooks.onAdd, hook)
+}
+
+// OnRemove registers a hook called before a component of the type is removed from an
+// entity, so the component can still be read. Removing an entity removes all of its components.
+func (cm *ComponentManager) OnRemove(componentType ComponentType, hook ComponentHook) {
+	hooks := cm.hooksFor(componentType)
+	hooks.onRemove = append(hooks.onRemove, hook)
+}
+
+// OnChange registers a hook called when a component of the type is marked changed
+func (cm *ComponentManager) OnChange(componentType ComponentType, hook ComponentHook) {
+	hooks := cm.hooksFor(componentType)
+	hooks.onChange = append(hooks.onChange, hook)
+}
+
+// MarkChanged reports that the entity's component was modified in place, recording
+// the change tick and calling the OnChange hooks
+func (cm *ComponentManager) MarkChanged(entity Entity, componentType ComponentType) {
+	component, found := cm.GetComponent(entity, componentType)
+	if !found {
+		return
+	}
+	cm.markTick(entity, componentType)
+	if hooks, exists := cm.hooks[componentType]; exists {
+		runHooks(hooks.onChange, entity, component)
+	}
+}
+
+func runHooks(hooks []ComponentHook, entity Entity, component ComponentInterface) {
+	for _, hook := range hooks {
+		hook(entity, component)
+	}
+}
+
+// OnAdd registers a typed hook called after a T is added to an entity
+func OnAdd[T any](world *World, hook func(entity Entity, component *T)) {
+	world.ComponentManager.OnAdd(ComponentTypeOf[T](), typedHook(hook))
+}
+
+// OnRemove registers a typed hook called before a T is removed from an entity
+func OnRemove[T any](world *World, hook func(entity Entity, component *T)) {
+	world.ComponentManager.OnRemove(ComponentTypeOf[T](), typedHook(hook))
+}
+
+// OnChange registers a typed hook called when an entity's T is marked changed
+func OnChange[T any](world *World, hook func(entity Entity, component *T)) {
+	world.ComponentManager.OnChange(ComponentTypeOf[T](), typedHook(hook))
+}
+
+func typedHook[T any](hook func(entity Entity, component *T)) ComponentHook {
+	return func(entity Entity, component ComponentInterface) {
+		hook(entity, any(component).(*T))
+	}
+}
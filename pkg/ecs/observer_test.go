@@ -0,0 +1,49 @@
+package ecs
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestObserversFireOnAddRemoveAndChange(t *testing.T) {
+	world := newTestWorld(t)
+	positions := NewStore[testPosition](world)
+
+	log := []string{}
+	OnAdd(world, func(entity Entity, pos *testPosition) {
+		log = append(log, "add", string(rune('0'+int(pos.X))))
+	})
+	OnRemove(world, func(entity Entity, pos *testPosition) {
+		// The component can still be read while it's being removed
+		if !positions.Has(entity) {
+			t.Errorf("Expected entity %d to still have its position in OnRemove", entity)
+		}
+		log = append(log, "remove", string(rune('0'+int(pos.X))))
+	})
+	OnChange(world, func(entity Entity, pos *testPosition) {
+		log = append(log, "change", string(rune('0'+int(pos.X))))
+	})
+
+	entity := world.EntityManager.CreateEntity()
+	positions.Add(entity, &testPosition{X: 1})
+	positions.Add(entity, &testPosition{X: 2})
+
+	pos, _ := positions.Get(entity)
+	pos.X = 3
+	positions.MarkChanged(entity)
+
+	world.RemoveEntity(entity)
+
+	// Marking a missing component doesn't fire anything
+	positions.MarkChanged(entity)
+
+	expected := []string{
+		"add", "1",
+		"remove", "1", "add", "2",
+		"change", "3",
+		"remove", "3",
+	}
+	if !slices.Equal(log, expected) {
+		t.Errorf("Expected hooks %v, got %v", expected, log)
+	}
+}
@@ -0,0 +1,151 @@
+package ecs
+
+import (
+	"fmt"
+	"slices"
+)
+
+// QueryFilter describes which entities a query matches
+type QueryFilter struct {
+	With     []ComponentType // Components an entity must have
+	Without  []ComponentType // Components an entity must not have
+	Optional []ComponentType // Components read with Optional if present, without affecting matching
+	Changed  []ComponentType // Components ChangedSince checks, from With or Optional
+}
+
+// Query is a cached set of entities matching a QueryFilter.
+// It is declared once and kept up to date by the ComponentManager as components
+// are added and removed, so reading it each frame costs nothing.
+type Query struct {
+	cm       *ComponentManager
+	filter   QueryFilter
+	entities []Entity // Sorted so iteration order is deterministic
+	changed  []Entity // Reused by ChangedSince
+}
+
+// NewQuery declares a query for entities that have all of the given components
+func (cm *ComponentManager) NewQuery(componentTypes []ComponentType) *Query {
+	return cm.NewQueryWithFilter(QueryFilter{With: componentTypes})
+}
+
+// NewQueryWithFilter declares a query for entities matching the filter.
+// Entities that already match are added immediately.
+func (cm *ComponentManager) NewQueryWithFilter(filter QueryFilter) *Query {
+	if len(filter.With) == 0 {
+		panic("ecs: a query needs at least one required component")
+	}
+
+	q := &Query{
+		cm: cm,
+		filter: QueryFilter{
+			With:     slices.Clone(filter.With),
+			Without:  slices.Clone(filter.Without),
+			Optional: slices.Clone(filter.Optional),
+			Changed:  slices.Clone(filter.Changed),
+		},
+		entities: []Entity{},
+	}
+
+	// Both required and excluded components can change whether an entity matches
+	for _, componentType := range slices.Concat(q.filter.With, q.filter.Without) {
+		cm.RegisterComponentType(componentType)
+		cm.queries[componentType] = append(cm.queries[componentType], q)
+	}
+	for _, componentType := range slices.Concat(q.filter.Optional, q.filter.Changed) {
+		cm.RegisterComponentType(componentType)
+	}
+
+	// Seed the query from the smallest required component map
+	smallest := q.filter.With[0]
+	for _, componentType := range q.filter.With[1:] {
+		if len(cm.components[componentType]) < len(cm.components[smallest]) {
+			smallest = componentType
+		}
+	}
+	for entity := range cm.components[smallest] {
+		if q.matches(cm, entity) {
+			q.insert(entity)
+		}
+	}
+
+	return q
+}
+
+// Filter returns the filter the query was declared with
+func (q *Query) Filter() QueryFilter {
+	return q.filter
+}
+
+// Entities returns the matching entities in ascending order.
+// The slice is owned by the query and must not be modified; it is only valid
+// until the next structural change to the components the query depends on.
+func (q *Query) Entities() []Entity {
+	return q.entities
+}
+
+// Len returns the number of matching entities
+func (q *Query) Len() int {
+	return len(q.entities)
+}
+
+// Contains reports whether the entity currently matches the query
+func (q *Query) Contains(entity Entity) bool {
+	_, found := slices.BinarySearch(q.entities, entity)
+	return found
+}
+
+// Optional returns the entity's component of a type the query declared as Optional,
+// or false if the entity doesn't have one. It panics for types that weren't declared,
+// so the filter stays a full list of what the query's users read.
+func (q *Query) Optional(entity Entity, componentType ComponentType) (ComponentInterface, bool) {
+	if !slices.Contains(q.filter.Optional, componentType) {
+		panic(fmt.Sprintf("ecs: %q is not an optional component of the query", componentType))
+	}
+	return q.cm.GetComponent(entity, componentType)
+}
+
+// GetOptional is Query.Optional for the component type T
+func GetOptional[T any](q *Query, entity Entity) (*T, bool) {
+	component, found := q.Optional(entity, ComponentTypeOf[T]())
+	if !found {
+		return nil, false
+	}
+	return any(component).(*T), true
+}
+
+func (q *Query) matches(cm *ComponentManager, entity Entity) bool {
+	for _, componentType := range q.filter.With {
+		if !cm.HasComponent(entity, componentType) {
+			return false
+		}
+	}
+	for _, componentType := range q.filter.Without {
+		if cm.HasComponent(entity, componentType) {
+			return false
+		}
+	}
+	return true
+}
+
+// update re-evaluates an entity after one of the query's components changed
+func (q *Query) update(cm *ComponentManager, entity Entity) {
+	if q.matches(cm, entity) {
+		q.insert(entity)
+	} else {
+		q.remove(entity)
+	}
+}
+
+func (q *Query) insert(entity Entity) {
+	i, found := slices.BinarySearch(q.entities, entity)
+	if !found {
+		q.entities = slices.Insert(q.entities, i, entity)
+	}
+}
+
+func (q *Query) remove(entity Entity) {
+	i, found := slices.BinarySearch(q.entities, entity)
+	if found {
+		q.entities = slices.Delete(q.entities, i, i+1)
+	}
+}
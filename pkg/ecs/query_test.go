@@ -0,0 +1,137 @@
+package ecs
+
+import (
+	"slices"
+	"testing"
+)
+
+const testVelocityType ComponentType = "test_velocity"
+
+type testVelocity struct {
+	Component
+	X, Y float64
+}
+
+func (c testVelocity) GetType() ComponentType {
+	return testVelocityType
+}
+
+func TestQueryTracksComponentChanges(t *testing.T) {
+	world := newTestWorld(t)
+	cm := world.ComponentManager
+
+	// Entities that exist before the query is declared are picked up
+	a := world.EntityManager.CreateEntity()
+	cm.AddComponent(a, testPositionType, &testPosition{})
+	cm.AddComponent(a, testVelocityType, &testVelocity{})
+
+	q := cm.NewQuery([]ComponentType{testPositionType, testVelocityType})
+	if !slices.Equal(q.Entities(), []Entity{a}) {
+		t.Fatalf("Expected query to contain [%d], got %v", a, q.Entities())
+	}
+
+	// Entities only join once they have every component
+	b := world.EntityManager.CreateEntity()
+	cm.AddComponent(b, testVelocityType, &testVelocity{})
+	if q.Contains(b) {
+		t.Errorf("Expected entity %d without a position not to match", b)
+	}
+	cm.AddComponent(b, testPositionType, &testPosition{})
+	if !slices.Equal(q.Entities(), []Entity{a, b}) {
+		t.Errorf("Expected query to contain [%d %d], got %v", a, b, q.Entities())
+	}
+
+	// Replacing a component doesn't duplicate the entity
+	cm.AddComponent(b, testPositionType, &testPosition{X: 1})
+	if q.Len() != 2 {
+		t.Errorf("Expected 2 entities after replacing a component, got %d", q.Len())
+	}
+
+	// Removing any required component drops the entity
+	cm.RemoveComponent(a, testVelocityType)
+	if !slices.Equal(q.Entities(), []Entity{b}) {
+		t.Errorf("Expected query to contain [%d], got %v", b, q.Entities())
+	}
+
+	world.RemoveEntity(b)
+	if q.Len() != 0 {
+		t.Errorf("Expected empty query after removing entity %d, got %v", b, q.Entities())
+	}
+}
+
+func TestQueryIterationDoesNotAllocate(t *testing.T) {
+	world := newTestWorld(t)
+	cm := world.ComponentManager
+	q := cm.NewQuery([]ComponentType{testPositionType})
+
+	for range 100 {
+		cm.AddComponent(world.EntityManager.CreateEntity(), testPositionType, &testPosition{})
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		for _, entity := range q.Entities() {
+			_ = entity
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("Expected query iteration not to allocate, got %v allocations", allocs)
+	}
+}
+
+func TestQueryFilterWithout(t *testing.T) {
+	world := newTestWorld(t)
+	cm := world.ComponentManager
+	q := cm.NewQueryWithFilter(QueryFilter{
+		With:    []ComponentType{testPositionType},
+		Without: []ComponentType{testVelocityType},
+	})
+
+	still := world.EntityManager.CreateEntity()
+	cm.AddComponent(still, testPositionType, &testPosition{})
+	if !q.Contains(still) {
+		t.Fatalf("Expected entity %d without a velocity to match", still)
+	}
+
+	// Adding an excluded component drops the entity, removing it brings it back
+	cm.AddComponent(still, testVelocityType, &testVelocity{})
+	if q.Contains(still) {
+		t.Errorf("Expected entity %d with a velocity not to match", still)
+	}
+	cm.RemoveComponent(still, testVelocityType)
+	if !q.Contains(still) {
+		t.Errorf("Expected entity %d to match again once its velocity is removed", still)
+	}
+}
+
+func TestQueryOptional(t *testing.T) {
+	world := newTestWorld(t)
+	cm := world.ComponentManager
+	q := cm.NewQueryWithFilter(QueryFilter{
+		With:     []ComponentType{testPositionType},
+		Optional: []ComponentType{testVelocityType},
+	})
+
+	// Optional components don't affect matching, but can be read when present
+	still := world.EntityManager.CreateEntity()
+	cm.AddComponent(still, testPositionType, &testPosition{})
+	moving := world.EntityManager.CreateEntity()
+	cm.AddComponent(moving, testPositionType, &testPosition{})
+	cm.AddComponent(moving, testVelocityType, &testVelocity{X: 2})
+	if q.Len() != 2 {
+		t.Fatalf("Expected both entities to match, got %v", q.Entities())
+	}
+	if _, found := GetOptional[testVelocity](q, still); found {
+		t.Errorf("Expected entity %d to have no velocity", still)
+	}
+	if velocity, found := GetOptional[testVelocity](q, moving); !found || velocity.X != 2 {
+		t.Errorf("Expected entity %d's velocity, got %v", moving, velocity)
+	}
+
+	// Reading a component the query didn't declare is a mistake
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected reading an undeclared optional component to panic")
+		}
+	}()
+	q.Optional(moving, "test_undeclared")
+}
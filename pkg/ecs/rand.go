@@ -0,0 +1,80 @@
+package ecs
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"math/rand/v2"
+)
+
+// Rand is the world's random number generator, a resource every world has. Runs
+// that start from the same seed and make the same calls get the same numbers, so
+// systems should draw from it rather than from math/rand. It isn't safe for
+// concurrent use, so systems that draw from it must declare ResourceOf[Rand]() in
+// their ResourceWrites. The schedule then never runs two of them at once, and a system
+// that leaves it out panics if it runs in a parallel batch.
+//
+// Its state is saved in snapshots, so a loaded game carries on with the same numbers.
+type Rand struct {
+	*rand.Rand
+	seed   uint64
+	source *rand.PCG
+}
+
+// NewRand creates a generator that always produces the same numbers for a seed
+func NewRand(seed uint64) *Rand {
+	source := rand.NewPCG(seed, seed)
+	return &Rand{
+		Rand:   rand.New(source),
+		seed:   seed,
+		source: source,
+	}
+}
+
+// Seed returns the seed the generator was created with
+func (r *Rand) Seed() uint64 {
+	return r.seed
+}
+
+// MarshalBinary encodes the seed and the current state of the generator
+func (r *Rand) MarshalBinary() ([]byte, error) {
+	state, err := r.source.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return binary.BigEndian.AppendUint64(state, r.seed), nil
+}
+
+// UnmarshalBinary restores a generator encoded by MarshalBinary
+func (r *Rand) UnmarshalBinary(data []byte) error {
+	if len(data) < 8 {
+		return fmt.Errorf("ecs: random generator state is too short")
+	}
+	split := len(data) - 8
+	source := &rand.PCG{}
+	if err := source.UnmarshalBinary(data[:split]); err != nil {
+		return err
+	}
+	r.seed = binary.BigEndian.Uint64(data[split:])
+	r.source = source
+	r.Rand = rand.New(source)
+	return nil
+}
+
+// MarshalText encodes the generator as text, for JSON snapshots
+func (r *Rand) MarshalText() ([]byte, error) {
+	data, err := r.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(base64.StdEncoding.EncodeToString(data)), nil
+}
+
+// UnmarshalText restores a generator encoded by MarshalText
+func (r *Rand) UnmarshalText(text []byte) error {
+	data, err := base64.StdEncoding.DecodeString(string(text))
+	if err != nil {
+		return err
+	}
+	return r.UnmarshalBinary(data)
+}
@@ -0,0 +1,48 @@
+package ecs
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Resources are singletons stored on the world by type, for state that belongs
+// to the game as a whole rather than to any one entity
+
+// InsertResource stores the resource on the world, replacing any existing one of the same type
+func InsertResource[T any](world *World, resource *T) {
+	world.resources[reflect.TypeFor[T]()] = resource
+}
+
+// GetResource returns the world's resource of type T, or false if there isn't one.
+// It panics if called from a system running in parallel that didn't declare T.
+func GetResource[T any](world *World) (*T, bool) {
+	resourceType := reflect.TypeFor[T]()
+	if world.batchResources != nil && !world.batchResources[resourceType] {
+		panic(fmt.Sprintf("ecs: %v resource used in a parallel batch without being declared", resourceType))
+	}
+	resource, found := world.resources[resourceType]
+	if !found {
+		return nil, false
+	}
+	return resource.(*T), true
+}
+
+// MustGetResource returns the world's resource of type T, panicking if there isn't one
+func MustGetResource[T any](world *World) *T {
+	resource, found := GetResource[T](world)
+	if !found {
+		panic(fmt.Sprintf("ecs: no %v resource has been inserted", reflect.TypeFor[T]()))
+	}
+	return resource
+}
+
+// HasResource reports whether the world has a resource of type T
+func HasResource[T any](world *World) bool {
+	_, found := world.resources[reflect.TypeFor[T]()]
+	return found
+}
+
+// RemoveResource removes the world's resource of type T, if there is one
+func RemoveResource[T any](world *World) {
+	delete(world.resources, reflect.TypeFor[T]())
+}
@@ -0,0 +1,33 @@
+package ecs
+
+import "testing"
+
+type testScore struct {
+	Points int
+}
+
+func TestResources(t *testing.T) {
+	world := newTestWorld(t)
+
+	if _, found := GetResource[testScore](world); found {
+		t.Fatalf("Expected no score resource before one is inserted")
+	}
+
+	InsertResource(world, &testScore{Points: 3})
+	MustGetResource[testScore](world).Points++
+	if score, _ := GetResource[testScore](world); score.Points != 4 {
+		t.Errorf("Expected the resource to be shared, got %d points", score.Points)
+	}
+
+	RemoveResource[testScore](world)
+	if HasResource[testScore](world) {
+		t.Errorf("Expected the score resource to be removed")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected MustGetResource to panic for a missing resource")
+		}
+	}()
+	MustGetResource[testScore](world)
+}
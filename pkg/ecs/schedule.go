@@ -0,0 +1,375 @@
+package ecs
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// Phase groups systems that run together. Phases run in the order they are declared.
+type Phase int
+
+const (
+	PhasePreUpdate Phase = iota
+	PhaseUpdate
+	PhasePostUpdate
+	PhaseRender
+)
+
+var phaseNames = map[Phase]string{
+	PhasePreUpdate:  "PreUpdate",
+	PhaseUpdate:     "Update",
+	PhasePostUpdate: "PostUpdate",
+	PhaseRender:     "Render",
+}
+
+func (p Phase) String() string {
+	if name, found := phaseNames[p]; found {
+		return name
+	}
+	return fmt.Sprintf("Phase(%d)", int(p))
+}
+
+// RunCondition decides each frame whether a system should run
+type RunCondition func(world *World) bool
+
+// SystemOptions controls where a system runs in the schedule
+type SystemOptions struct {
+	Name   string       // Defaults to the system's type name, e.g. "WaveSystem"
+	Phase  Phase        // Defaults to PhaseUpdate
+	Before []string     // Names of systems this one must run before
+	After  []string     // Names of systems this one must run after
+	RunIf  RunCondition // Skip the system on frames where this returns false
+}
+
+type scheduledSystem struct {
+	system  System
+	options SystemOptions
+	access  *SystemAccess // nil if the system doesn't declare its access
+	order   int           // Insertion order, used to break ties
+	lastRun Tick          // Change tick the system last ran at, see ChangeTracker
+}
+
+// Schedule orders systems by phase and by the Before/After constraints between them.
+// With more than one worker, consecutive systems whose declared component access
+// doesn't conflict are run concurrently.
+type Schedule struct {
+	systems []*scheduledSystem
+	ordered []*scheduledSystem
+	batches [][]*scheduledSystem // Systems in a batch run together, then commands are applied
+	workers int
+	built   bool
+}
+
+func NewSchedule() *Schedule {
+	return &Schedule{
+		systems: []*scheduledSystem{},
+		workers: 1,
+	}
+}
+
+// SetWorkers sets how many systems may run at once. 1 (the default) runs every
+// system in order on the calling goroutine. Entity IDs handed out by systems
+// running in parallel depend on timing, so use 1 when runs must be reproducible.
+func (s *Schedule) SetWorkers(workers int) {
+	s.workers = max(1, workers)
+	s.built = false
+}
+
+// SystemName returns the name a system is scheduled under when none is given
+func SystemName(system System) string {
+	t := reflect.TypeOf(system)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Name()
+}
+
+// Add schedules a system. The order is worked out again on the next Build.
+func (s *Schedule) Add(system System, options SystemOptions) {
+	if options.Name == "" {
+		options.Name = SystemName(system)
+	}
+	sys := &scheduledSystem{
+		system:  system,
+		options: options,
+		order:   len(s.systems),
+	}
+	if declarer, ok := system.(AccessDeclarer); ok {
+		access := declarer.Access()
+		sys.access = &access
+	}
+	s.systems = append(s.systems, sys)
+	s.built = false
+}
+
+// Build validates the constraints and works out the run order.
+// Unknown or duplicate names, cycles, and constraints that contradict the
+// phase order are reported as errors.
+func (s *Schedule) Build() error {
+	byName := make(map[string]*scheduledSystem, len(s.systems))
+	for _, sys := range s.systems {
+		if _, exists := byName[sys.options.Name]; exists {
+			return fmt.Errorf("ecs: system %q is scheduled more than once", sys.options.Name)
+		}
+		if _, known := phaseNames[sys.options.Phase]; !known {
+			return fmt.Errorf(
+				"ecs: system %q has unknown phase %v",
+				sys.options.Name, sys.options.Phase,
+			)
+		}
+		byName[sys.options.Name] = sys
+	}
+
+	// Collect the edges (first runs before second) between systems in the same phase
+	successors := make(map[*scheduledSystem][]*scheduledSystem)
+	inDegree := make(map[*scheduledSystem]int)
+	constrained := make(map[[2]*scheduledSystem]bool)
+	addEdge := func(first, second *scheduledSystem) error {
+		switch {
+		case first.options.Phase > second.options.Phase:
+			return fmt.Errorf(
+				"ecs: system %q (%v) must run before %q (%v), but its phase runs later",
+				first.options.Name, first.options.Phase,
+				second.options.Name, second.options.Phase,
+			)
+		case first.options.Phase < second.options.Phase:
+			// The phase order already guarantees it
+			return nil
+		}
+		successors[first] = append(successors[first], second)
+		inDegree[second]++
+		constrained[[2]*scheduledSystem{first, second}] = true
+		constrained[[2]*scheduledSystem{second, first}] = true
+		return nil
+	}
+
+	for _, sys := range s.systems {
+		for _, name := range sys.options.Before {
+			other, found := byName[name]
+			if !found {
+				return fmt.Errorf(
+					"ecs: system %q runs before unknown system %q",
+					sys.options.Name, name,
+				)
+			}
+			if err := addEdge(sys, other); err != nil {
+				return err
+			}
+		}
+		for _, name := range sys.options.After {
+			other, found := byName[name]
+			if !found {
+				return fmt.Errorf(
+					"ecs: system %q runs after unknown system %q",
+					sys.options.Name, name,
+				)
+			}
+			if err := addEdge(other, sys); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Topological sort, always taking the earliest added system that is ready,
+	// so unconstrained systems keep the order they were added in
+	ordered := make([]*scheduledSystem, 0, len(s.systems))
+	ready := []*scheduledSystem{}
+	for _, sys := range s.systems {
+		if inDegree[sys] == 0 {
+			ready = append(ready, sys)
+		}
+	}
+	for len(ready) > 0 {
+		next := slices.MinFunc(ready, compareScheduled)
+		ready = slices.DeleteFunc(ready, func(sys *scheduledSystem) bool { return sys == next })
+		ordered = append(ordered, next)
+
+		for _, successor := range successors[next] {
+			inDegree[successor]--
+			if inDegree[successor] == 0 {
+				ready = append(ready, successor)
+			}
+		}
+	}
+
+	if len(ordered) != len(s.systems) {
+		cycle := []string{}
+		for _, sys := range s.systems {
+			if inDegree[sys] > 0 {
+				cycle = append(cycle, sys.options.Name)
+			}
+		}
+		return fmt.Errorf("ecs: ordering constraints form a cycle between %v", cycle)
+	}
+
+	s.ordered = ordered
+	s.batches = s.buildBatches(constrained)
+	s.built = true
+	return nil
+}
+
+// buildBatches groups consecutive systems that can run at the same time: they
+// share a phase, declare non-conflicting access, and aren't ordered against each other.
+// Batches are runs of the sorted order, so indirect ordering constraints always
+// pass through a system that ends the batch.
+func (s *Schedule) buildBatches(constrained map[[2]*scheduledSystem]bool) [][]*scheduledSystem {
+	batches := [][]*scheduledSystem{}
+	for _, sys := range s.ordered {
+		if n := len(batches); n > 0 && s.canJoin(batches[n-1], sys, constrained) {
+			batches[n-1] = append(batches[n-1], sys)
+			continue
+		}
+		batches = append(batches, []*scheduledSystem{sys})
+	}
+	return batches
+}
+
+func (s *Schedule) canJoin(
+	batch []*scheduledSystem,
+	sys *scheduledSystem,
+	constrained map[[2]*scheduledSystem]bool,
+) bool {
+	if s.workers < 2 || sys.access == nil {
+		return false
+	}
+	for _, other := range batch {
+		if other.access == nil ||
+			other.options.Phase != sys.options.Phase ||
+			constrained[[2]*scheduledSystem{other, sys}] ||
+			other.access.ConflictsWith(*sys.access) {
+			return false
+		}
+	}
+	return true
+}
+
+// compareScheduled orders systems by phase, then by insertion order
+func compareScheduled(a, b *scheduledSystem) int {
+	if a.options.Phase != b.options.Phase {
+		return int(a.options.Phase) - int(b.options.Phase)
+	}
+	return a.order - b.order
+}
+
+// Names returns the system names in the order they run
+func (s *Schedule) Names() ([]string, error) {
+	if !s.built {
+		if err := s.Build(); err != nil {
+			return nil, err
+		}
+	}
+
+	names := make([]string, len(s.ordered))
+	for i, sys := range s.ordered {
+		names[i] = sys.options.Name
+	}
+	return names, nil
+}
+
+// Batches returns the system names grouped by the batches they run in
+func (s *Schedule) Batches() ([][]string, error) {
+	if !s.built {
+		if err := s.Build(); err != nil {
+			return nil, err
+		}
+	}
+
+	batches := make([][]string, len(s.batches))
+	for i, batch := range s.batches {
+		for _, sys := range batch {
+			batches[i] = append(batches[i], sys.options.Name)
+		}
+	}
+	return batches, nil
+}
+
+// Run updates every system in order, applying the world's commands after each batch
+func (s *Schedule) Run(world *World, deltaTime float64) {
+	if !s.built {
+		if err := s.Build(); err != nil {
+			panic(err)
+		}
+	}
+
+	runnable := []*scheduledSystem{}
+	for _, batch := range s.batches {
+		// Run conditions are checked up front, on this goroutine
+		runnable = runnable[:0]
+		for _, sys := range batch {
+			if sys.options.RunIf == nil || sys.options.RunIf(world) {
+				runnable = append(runnable, sys)
+			}
+		}
+
+		world.ComponentManager.advanceTick()
+		runTick := world.ChangeTick()
+		for _, sys := range runnable {
+			if tracker, ok := sys.system.(ChangeTracker); ok {
+				tracker.SetLastRun(sys.lastRun)
+			}
+			sys.lastRun = runTick
+		}
+		if len(runnable) == 1 {
+			runnable[0].system.Update(world, deltaTime)
+		} else if len(runnable) > 1 {
+			s.runParallel(world, deltaTime, runnable)
+		}
+
+		// Sync point: each batch sees the structural changes of the ones before it
+		world.ComponentManager.advanceTick()
+		world.commands.Apply()
+	}
+}
+
+// batchResources returns the resources a batch declared, which are the only ones
+// its systems may fetch while they run together
+func batchResources(batch []*scheduledSystem) map[reflect.Type]bool {
+	declared := map[reflect.Type]bool{}
+	for _, sys := range batch {
+		for _, resource := range sys.access.resources() {
+			declared[resource] = true
+		}
+	}
+	return declared
+}
+
+// runParallel runs a batch on a pool of up to s.workers goroutines
+func (s *Schedule) runParallel(world *World, deltaTime float64, batch []*scheduledSystem) {
+	jobs := make(chan System, len(batch))
+	for _, sys := range batch {
+		jobs <- sys.system
+	}
+	close(jobs)
+
+	world.batchResources = batchResources(batch)
+	defer func() { world.batchResources = nil }()
+
+	// A panic in a worker is passed on to the caller, rather than taking the process down
+	var (
+		wg       sync.WaitGroup
+		panicMu  sync.Mutex
+		panicked any
+	)
+	for range min(s.workers, len(batch)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					panicMu.Lock()
+					panicked = r
+					panicMu.Unlock()
+				}
+			}()
+			for system := range jobs {
+				system.Update(world, deltaTime)
+			}
+		}()
+	}
+	wg.Wait()
+	if panicked != nil {
+		panic(panicked)
+	}
+}
@@ -0,0 +1,307 @@
+package ecs
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// recordSystem appends its name to a shared log when it runs
+type recordSystem struct {
+	name string
+	log  *[]string
+}
+
+func (s *recordSystem) Update(world *World, deltaTime float64) {
+	*s.log = append(*s.log, s.name)
+}
+
+func TestScheduleOrdersByPhaseAndConstraints(t *testing.T) {
+	world := newTestWorld(t)
+	log := []string{}
+	add := func(name string, options SystemOptions) {
+		options.Name = name
+		world.AddSystemWithOptions(&recordSystem{name: name, log: &log}, options)
+	}
+
+	add("render", SystemOptions{Phase: PhaseRender})
+	add("shoot", SystemOptions{After: []string{"target"}})
+	add("target", SystemOptions{After: []string{"move"}})
+	add("move", SystemOptions{})
+	add("spawn", SystemOptions{Phase: PhasePreUpdate, Before: []string{"move"}})
+	add("collide", SystemOptions{Phase: PhasePostUpdate})
+
+	if err := world.BuildSchedule(); err != nil {
+		t.Fatalf("Expected the schedule to build, got %v", err)
+	}
+
+	world.Update(1.0 / 60)
+	expected := []string{"spawn", "move", "target", "shoot", "collide", "render"}
+	if !slices.Equal(log, expected) {
+		t.Errorf("Expected run order %v, got %v", expected, log)
+	}
+}
+
+func TestScheduleRunConditions(t *testing.T) {
+	world := newTestWorld(t)
+	log := []string{}
+	paused := true
+	world.AddSystemWithOptions(&recordSystem{name: "sim", log: &log}, SystemOptions{
+		RunIf: func(world *World) bool { return !paused },
+	})
+
+	world.Update(1.0 / 60)
+	paused = false
+	world.Update(1.0 / 60)
+
+	if !slices.Equal(log, []string{"sim"}) {
+		t.Errorf("Expected the system to run only once unpaused, got %v", log)
+	}
+}
+
+func TestScheduleReportsBadConstraints(t *testing.T) {
+	tests := []struct {
+		name    string
+		systems map[string]SystemOptions
+		err     string
+	}{
+		{
+			name:    "unknown system",
+			systems: map[string]SystemOptions{"a": {After: []string{"missing"}}},
+			err:     "unknown system",
+		},
+		{
+			name: "cycle",
+			systems: map[string]SystemOptions{
+				"a": {After: []string{"b"}},
+				"b": {After: []string{"a"}},
+			},
+			err: "cycle",
+		},
+		{
+			name: "phase contradiction",
+			systems: map[string]SystemOptions{
+				"a": {Phase: PhasePostUpdate, Before: []string{"b"}},
+				"b": {Phase: PhaseUpdate},
+			},
+			err: "phase runs later",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			world := newTestWorld(t)
+			log := []string{}
+			for name, options := range test.systems {
+				options.Name = name
+				world.AddSystemWithOptions(&recordSystem{name: name, log: &log}, options)
+			}
+
+			err := world.BuildSchedule()
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("Expected an error containing %q, got %v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestSystemNameDefaultsToTypeName(t *testing.T) {
+	if name := SystemName(&recordSystem{}); name != "recordSystem" {
+		t.Errorf("Expected default name recordSystem, got %q", name)
+	}
+}
+
+// moveSystem writes the position of every entity with a velocity
+type moveSystem struct {
+	positions *Store[testPosition]
+	velocity  *Query
+}
+
+func (s *moveSystem) Access() SystemAccess {
+	return SystemAccess{
+		Reads:  []ComponentType{testVelocityType},
+		Writes: []ComponentType{testPositionType},
+	}
+}
+
+func (s *moveSystem) Update(world *World, deltaTime float64) {
+	for _, entity := range s.velocity.Entities() {
+		pos, _ := s.positions.Get(entity)
+		pos.X += deltaTime
+	}
+	world.Commands().CreateEntity()
+}
+
+// accelerateSystem writes velocities
+type accelerateSystem struct {
+	velocities *Store[testVelocity]
+}
+
+func (s *accelerateSystem) Access() SystemAccess {
+	return SystemAccess{Writes: []ComponentType{testVelocityType}}
+}
+
+func (s *accelerateSystem) Update(world *World, deltaTime float64) {
+	s.velocities.Each(func(entity Entity, vel *testVelocity) {
+		vel.X += deltaTime
+	})
+	world.Commands().CreateEntity()
+}
+
+// scoreSystem only reads positions
+type scoreSystem struct {
+	positions *Store[testPosition]
+	total     float64
+}
+
+func (s *scoreSystem) Access() SystemAccess {
+	return SystemAccess{Reads: []ComponentType{testPositionType}}
+}
+
+func (s *scoreSystem) Update(world *World, deltaTime float64) {
+	s.positions.Each(func(entity Entity, pos *testPosition) {
+		s.total += pos.X
+	})
+}
+
+func TestScheduleRunsNonConflictingSystemsTogether(t *testing.T) {
+	world := newTestWorld(t)
+	world.SetWorkers(4)
+
+	positions := NewStore[testPosition](world)
+	velocities := NewStore[testVelocity](world)
+	for range 50 {
+		entity := world.EntityManager.CreateEntity()
+		positions.Add(entity, &testPosition{})
+		velocities.Add(entity, &testVelocity{})
+	}
+
+	// move and score both touch positions, so score has to wait for move;
+	// accelerate writes velocities, which move reads, so it can't join move either
+	world.AddSystem(&moveSystem{
+		positions: positions,
+		velocity:  world.ComponentManager.NewQuery([]ComponentType{testVelocityType}),
+	})
+	world.AddSystem(&scoreSystem{positions: positions})
+	world.AddSystem(&accelerateSystem{velocities: velocities})
+	world.AddSystem(&recordSystem{name: "undeclared", log: &[]string{}})
+
+	batches, err := world.Schedule().Batches()
+	if err != nil {
+		t.Fatalf("Expected the schedule to build, got %v", err)
+	}
+	expected := [][]string{
+		{"moveSystem"},
+		{"scoreSystem", "accelerateSystem"},
+		{"recordSystem"},
+	}
+	if !slices.EqualFunc(batches, expected, slices.Equal) {
+		t.Errorf("Expected batches %v, got %v", expected, batches)
+	}
+
+	for range 10 {
+		world.Update(1.0 / 60)
+	}
+
+	// 50 entities, plus the two entities queued by move and accelerate each frame
+	if n := world.EntityManager.Len(); n != 70 {
+		t.Errorf("Expected 70 entities, got %d", n)
+	}
+}
+
+// resourceSystem declares the given access and fetches the score resource when it runs
+type resourceSystem struct {
+	access SystemAccess
+}
+
+func (s *resourceSystem) Access() SystemAccess {
+	return s.access
+}
+
+func (s *resourceSystem) Update(world *World, deltaTime float64) {
+	MustGetResource[testScore](world).Points++
+}
+
+func TestScheduleKeepsResourceWritersApart(t *testing.T) {
+	world := newTestWorld(t)
+	world.SetWorkers(4)
+	InsertResource(world, &testScore{})
+
+	reads := SystemAccess{ResourceReads: []reflect.Type{ResourceOf[testScore]()}}
+	writes := SystemAccess{ResourceWrites: []reflect.Type{ResourceOf[testScore]()}}
+	add := func(name string, access SystemAccess) {
+		world.AddSystemWithOptions(&resourceSystem{access: access}, SystemOptions{Name: name})
+	}
+	add("firstReader", reads)
+	add("secondReader", reads)
+	add("firstWriter", writes)
+	add("secondWriter", writes)
+
+	batches, err := world.Schedule().Batches()
+	if err != nil {
+		t.Fatalf("Expected the schedule to build, got %v", err)
+	}
+	expected := [][]string{
+		{"firstReader", "secondReader"},
+		{"firstWriter"},
+		{"secondWriter"},
+	}
+	if !slices.EqualFunc(batches, expected, slices.Equal) {
+		t.Errorf("Expected batches %v, got %v", expected, batches)
+	}
+}
+
+func TestScheduleRejectsUndeclaredResourcesInParallel(t *testing.T) {
+	world := newTestWorld(t)
+	world.SetWorkers(4)
+	InsertResource(world, &testScore{})
+
+	// Neither declares the score, so they can share a batch but mustn't touch it
+	world.AddSystemWithOptions(&resourceSystem{}, SystemOptions{Name: "first"})
+	world.AddSystemWithOptions(&resourceSystem{}, SystemOptions{Name: "second"})
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected fetching an undeclared resource in a parallel batch to panic")
+		}
+	}()
+	world.Update(1.0 / 60)
+}
+
+// drawSystem draws a number from the world's Rand
+type drawSystem struct {
+	drawn *[]uint64
+}
+
+func (s *drawSystem) Access() SystemAccess {
+	return SystemAccess{ResourceWrites: []reflect.Type{ResourceOf[Rand]()}}
+}
+
+func (s *drawSystem) Update(world *World, deltaTime float64) {
+	*s.drawn = append(*s.drawn, MustGetResource[Rand](world).Uint64())
+}
+
+func TestScheduleRunsRandUsersOneAtATime(t *testing.T) {
+	world := newTestWorld(t)
+	world.SetWorkers(4)
+
+	drawn := []uint64{}
+	for _, name := range []string{"first", "second", "third"} {
+		world.AddSystemWithOptions(&drawSystem{drawn: &drawn}, SystemOptions{Name: name})
+	}
+
+	batches, err := world.Schedule().Batches()
+	if err != nil {
+		t.Fatalf("Expected the schedule to build, got %v", err)
+	}
+	expected := [][]string{{"first"}, {"second"}, {"third"}}
+	if !slices.EqualFunc(batches, expected, slices.Equal) {
+		t.Errorf("Expected batches %v, got %v", expected, batches)
+	}
+
+	world.Update(1.0 / 60)
+	if len(drawn) != 3 {
+		t.Errorf("Expected 3 numbers drawn, got %d", len(drawn))
+	}
+}
@@ -0,0 +1,295 @@
+package ecs
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+)
+
+// A snapshot holds every entity, every component and the registered resources of a
+// world. Components are encoded by their concrete Go type, so each component type
+// saved must be registered with RegisterComponent (NewStore does this too).
+// Resources are only saved when registered with RegisterResource; the others are
+// expected to be derived from components, e.g. by observers, and rebuilt on load.
+
+// SnapshotVersion is the version of the snapshot format written by SaveSnapshot
+const SnapshotVersion = 1
+
+// SnapshotFormat selects how a snapshot is encoded
+type SnapshotFormat int
+
+const (
+	SnapshotJSON   SnapshotFormat = iota // Readable, for bug reports and golden files
+	SnapshotBinary                       // Compact, using encoding/gob
+)
+
+// RegisterComponent records T as the concrete type of its ComponentType, so
+// components of that type can be saved and loaded
+func RegisterComponent[T any](world *World) {
+	componentType := ComponentTypeOf[T]()
+	world.ComponentManager.RegisterComponentType(componentType)
+	world.componentTypes[componentType] = reflect.TypeFor[T]()
+}
+
+// RegisterResource includes the world's resource of type T in snapshots, under a
+// name that must stay the same for saved snapshots to load
+func RegisterResource[T any](world *World, name string) {
+	world.resourceTypes[name] = reflect.TypeFor[T]()
+}
+
+// snapshot is the encoded form of a world. P is the encoding of a value:
+// raw JSON, or gob bytes.
+type snapshot[P any] struct {
+	Version    int
+	Entities   entitySnapshot
+	Components []componentColumn[P]
+	Resources  []resourceEntry[P]
+}
+
+type entitySnapshot struct {
+	Generations []uint32
+	Alive       []bool
+	Free        []uint32
+}
+
+// componentColumn holds every component of one type. Values encodes a slice of the
+// concrete component type, in the same order as Entities.
+type componentColumn[P any] struct {
+	Type     ComponentType
+	Entities []Entity
+	Values   P
+}
+
+type resourceEntry[P any] struct {
+	Name  string
+	Value P
+}
+
+// snapshotCodec encodes single values for a SnapshotFormat
+type snapshotCodec[P any] struct {
+	marshal   func(value any) (P, error)
+	unmarshal func(data P, value any) error
+}
+
+var jsonCodec = snapshotCodec[json.RawMessage]{
+	marshal: func(value any) (json.RawMessage, error) {
+		return json.Marshal(value)
+	},
+	unmarshal: func(data json.RawMessage, value any) error {
+		return json.Unmarshal(data, value)
+	},
+}
+
+var gobCodec = snapshotCodec[[]byte]{
+	marshal: func(value any) ([]byte, error) {
+		var buffer bytes.Buffer
+		err := gob.NewEncoder(&buffer).Encode(value)
+		return buffer.Bytes(), err
+	},
+	unmarshal: func(data []byte, value any) error {
+		return gob.NewDecoder(bytes.NewReader(data)).Decode(value)
+	},
+}
+
+// SaveSnapshot writes every entity, component and registered resource to out.
+// Call it between updates, when there are no pending commands.
+func (w *World) SaveSnapshot(out io.Writer, format SnapshotFormat) error {
+	switch format {
+	case SnapshotJSON:
+		data, err := encodeSnapshot(w, jsonCodec)
+		if err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(data)
+	case SnapshotBinary:
+		data, err := encodeSnapshot(w, gobCodec)
+		if err != nil {
+			return err
+		}
+		return gob.NewEncoder(out).Encode(data)
+	default:
+		return fmt.Errorf("ecs: unknown snapshot format %d", format)
+	}
+}
+
+// LoadSnapshot replaces every entity and component, and the registered resources in
+// the snapshot, with the ones read from in. Components are added through the
+// ComponentManager, so queries and observers see them as newly added.
+// Call it between updates, when there are no pending commands.
+func (w *World) LoadSnapshot(in io.Reader, format SnapshotFormat) error {
+	switch format {
+	case SnapshotJSON:
+		var data snapshot[json.RawMessage]
+		if err := json.NewDecoder(in).Decode(&data); err != nil {
+			return fmt.Errorf("ecs: reading snapshot: %w", err)
+		}
+		return decodeSnapshot(w, jsonCodec, &data)
+	case SnapshotBinary:
+		var data snapshot[[]byte]
+		if err := gob.NewDecoder(in).Decode(&data); err != nil {
+			return fmt.Errorf("ecs: reading snapshot: %w", err)
+		}
+		return decodeSnapshot(w, gobCodec, &data)
+	default:
+		return fmt.Errorf("ecs: unknown snapshot format %d", format)
+	}
+}
+
+func encodeSnapshot[P any](w *World, codec snapshotCodec[P]) (*snapshot[P], error) {
+	em := w.EntityManager
+	data := &snapshot[P]{
+		Version: SnapshotVersion,
+		Entities: entitySnapshot{
+			Generations: slices.Clone(em.generations),
+			Alive:       slices.Clone(em.alive),
+			Free:        slices.Clone(em.free),
+		},
+	}
+
+	// Component types and entities are sorted so the same world always encodes the same
+	componentTypes := make([]ComponentType, 0, len(w.ComponentManager.components))
+	for componentType := range w.ComponentManager.components {
+		componentTypes = append(componentTypes, componentType)
+	}
+	slices.Sort(componentTypes)
+
+	for _, componentType := range componentTypes {
+		componentMap := w.ComponentManager.components[componentType]
+		if len(componentMap) == 0 {
+			continue
+		}
+		concrete, registered := w.componentTypes[componentType]
+		if !registered {
+			return nil, fmt.Errorf("ecs: component type %q is not registered", componentType)
+		}
+
+		column := componentColumn[P]{Type: componentType}
+		for entity := range componentMap {
+			column.Entities = append(column.Entities, entity)
+		}
+		slices.Sort(column.Entities)
+
+		values := reflect.MakeSlice(reflect.SliceOf(concrete), 0, len(column.Entities))
+		for _, entity := range column.Entities {
+			values = reflect.Append(values, reflect.ValueOf(componentMap[entity]).Elem())
+		}
+		encoded, err := codec.marshal(values.Interface())
+		if err != nil {
+			return nil, fmt.Errorf("ecs: encoding %q components: %w", componentType, err)
+		}
+		column.Values = encoded
+		data.Components = append(data.Components, column)
+	}
+
+	names := make([]string, 0, len(w.resourceTypes))
+	for name := range w.resourceTypes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		resource, found := w.resources[w.resourceTypes[name]]
+		if !found {
+			continue
+		}
+		encoded, err := codec.marshal(resource)
+		if err != nil {
+			return nil, fmt.Errorf("ecs: encoding resource %q: %w", name, err)
+		}
+		data.Resources = append(data.Resources, resourceEntry[P]{Name: name, Value: encoded})
+	}
+
+	return data, nil
+}
+
+func decodeSnapshot[P any](w *World, codec snapshotCodec[P], data *snapshot[P]) error {
+	if data.Version != SnapshotVersion {
+		return fmt.Errorf(
+			"ecs: snapshot version %d is not supported, expected %d",
+			data.Version, SnapshotVersion,
+		)
+	}
+	entities := data.Entities
+	if len(entities.Generations) == 0 || len(entities.Generations) != len(entities.Alive) {
+		return fmt.Errorf("ecs: snapshot has inconsistent entity state")
+	}
+
+	// Decode everything before touching the world, so a bad snapshot leaves it as it was
+	type decodedColumn struct {
+		componentType ComponentType
+		entities      []Entity
+		values        reflect.Value
+	}
+	columns := make([]decodedColumn, 0, len(data.Components))
+	for _, column := range data.Components {
+		concrete, registered := w.componentTypes[column.Type]
+		if !registered {
+			return fmt.Errorf("ecs: component type %q is not registered", column.Type)
+		}
+		values := reflect.New(reflect.SliceOf(concrete))
+		if err := codec.unmarshal(column.Values, values.Interface()); err != nil {
+			return fmt.Errorf("ecs: decoding %q components: %w", column.Type, err)
+		}
+		if values.Elem().Len() != len(column.Entities) {
+			return fmt.Errorf(
+				"ecs: snapshot has %d %q components for %d entities",
+				values.Elem().Len(), column.Type, len(column.Entities),
+			)
+		}
+		columns = append(columns, decodedColumn{column.Type, column.Entities, values.Elem()})
+	}
+
+	resources := make(map[reflect.Type]reflect.Value, len(data.Resources))
+	for _, entry := range data.Resources {
+		resourceType, registered := w.resourceTypes[entry.Name]
+		if !registered {
+			return fmt.Errorf("ecs: resource %q is not registered", entry.Name)
+		}
+		value := reflect.New(resourceType)
+		if err := codec.unmarshal(entry.Value, value.Interface()); err != nil {
+			return fmt.Errorf("ecs: decoding resource %q: %w", entry.Name, err)
+		}
+		resources[resourceType] = value
+	}
+
+	// Clear the world, letting queries and observers see every component go
+	for _, entity := range w.EntityManager.GetAllEntities() {
+		w.ComponentManager.RemoveAllComponents(entity)
+	}
+
+	em := w.EntityManager
+	em.generations = slices.Clone(entities.Generations)
+	em.alive = slices.Clone(entities.Alive)
+	em.free = append([]uint32{}, entities.Free...) // Never nil, so it encodes the same as before
+	em.count = 0
+	for _, alive := range em.alive {
+		if alive {
+			em.count++
+		}
+	}
+
+	// Existing resources are overwritten in place, so pointers to them stay valid
+	for resourceType, value := range resources {
+		if existing, found := w.resources[resourceType]; found {
+			reflect.ValueOf(existing).Elem().Set(value.Elem())
+		} else {
+			w.resources[resourceType] = value.Interface()
+		}
+	}
+
+	w.ComponentManager.advanceTick()
+	for _, column := range columns {
+		for i, entity := range column.entities {
+			component := column.values.Index(i).Addr().Interface().(ComponentInterface)
+			w.ComponentManager.AddComponent(entity, column.componentType, component)
+		}
+	}
+
+	return nil
+}
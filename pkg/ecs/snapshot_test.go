@@ -0,0 +1,161 @@
+package ecs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// newSnapshotWorld creates a world with everything a snapshot of it needs registered
+func newSnapshotWorld(t *testing.T) *World {
+	world := newTestWorld(t)
+	RegisterComponent[testPosition](world)
+	RegisterComponent[testVelocity](world)
+	RegisterResource[testScore](world, "score")
+	return world
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	for _, format := range []SnapshotFormat{SnapshotJSON, SnapshotBinary} {
+		world := newSnapshotWorld(t)
+		cm := world.ComponentManager
+
+		a := world.EntityManager.CreateEntity()
+		b := world.EntityManager.CreateEntity()
+		c := world.EntityManager.CreateEntity()
+		cm.AddComponent(a, testPositionType, &testPosition{X: 1, Y: 2})
+		cm.AddComponent(a, testVelocityType, &testVelocity{X: 0.5})
+		cm.AddComponent(b, testPositionType, &testPosition{X: 3})
+		cm.AddComponent(c, testPositionType, &testPosition{X: 4})
+		world.RemoveEntity(b) // Leaves a recycled index behind
+		InsertResource(world, &testScore{Points: 7})
+
+		var saved bytes.Buffer
+		if err := world.SaveSnapshot(&saved, format); err != nil {
+			t.Fatalf("Format %d: expected the world to save, got %v", format, err)
+		}
+
+		// Load into a world that already has state of its own
+		restored := newSnapshotWorld(t)
+		stale := restored.EntityManager.CreateEntity()
+		restored.ComponentManager.AddComponent(stale, testVelocityType, &testVelocity{})
+		score := &testScore{Points: 1}
+		InsertResource(restored, score)
+
+		moving := restored.ComponentManager.NewQuery(
+			[]ComponentType{testPositionType, testVelocityType},
+		)
+		added := 0
+		OnAdd(restored, func(entity Entity, pos *testPosition) {
+			added++
+		})
+
+		if err := restored.LoadSnapshot(bytes.NewReader(saved.Bytes()), format); err != nil {
+			t.Fatalf("Format %d: expected the snapshot to load, got %v", format, err)
+		}
+
+		if added != 2 {
+			t.Errorf("Format %d: expected observers to see 2 positions added, got %d", format, added)
+		}
+		if moving.Len() != 1 || !moving.Contains(a) {
+			t.Errorf("Format %d: expected the query to hold [%d], got %v",
+				format, a, moving.Entities())
+		}
+		if restored.EntityManager.IsAlive(b) || !restored.EntityManager.IsAlive(c) {
+			t.Errorf("Format %d: expected entity liveness to be restored", format)
+		}
+		pos, _ := restored.ComponentManager.GetComponent(c, testPositionType)
+		if pos.(*testPosition).X != 4 {
+			t.Errorf("Format %d: expected entity %d at X 4, got %v", format, c, pos)
+		}
+		if score.Points != 7 || MustGetResource[testScore](restored) != score {
+			t.Errorf("Format %d: expected the score resource to be overwritten in place", format)
+		}
+
+		// Saving the restored world gives the same snapshot
+		var resaved bytes.Buffer
+		if err := restored.SaveSnapshot(&resaved, format); err != nil {
+			t.Fatalf("Format %d: expected the restored world to save, got %v", format, err)
+		}
+		if !bytes.Equal(saved.Bytes(), resaved.Bytes()) {
+			t.Errorf("Format %d: expected the restored world to save the same snapshot", format)
+		}
+
+		// Both worlds hand out the same entity next
+		next := world.EntityManager.CreateEntity()
+		if restoredNext := restored.EntityManager.CreateEntity(); restoredNext != next {
+			t.Errorf("Format %d: expected the next entity to be %d, got %d",
+				format, next, restoredNext)
+		}
+	}
+}
+
+func TestSnapshotKeepsAnEmptyFreeList(t *testing.T) {
+	// The binary format can't tell an empty list from none, which the JSON one can
+	world := newSnapshotWorld(t)
+	world.EntityManager.CreateEntity()
+
+	var before, saved, after bytes.Buffer
+	if err := world.SaveSnapshot(&before, SnapshotJSON); err != nil {
+		t.Fatalf("Expected the world to save, got %v", err)
+	}
+	if err := world.SaveSnapshot(&saved, SnapshotBinary); err != nil {
+		t.Fatalf("Expected the world to save, got %v", err)
+	}
+	if err := world.LoadSnapshot(&saved, SnapshotBinary); err != nil {
+		t.Fatalf("Expected the snapshot to load, got %v", err)
+	}
+	if err := world.SaveSnapshot(&after, SnapshotJSON); err != nil {
+		t.Fatalf("Expected the loaded world to save, got %v", err)
+	}
+	if !bytes.Equal(before.Bytes(), after.Bytes()) {
+		t.Errorf("Expected the same JSON snapshot after a binary round trip\nbefore: %s\nafter:  %s",
+			before.String(), after.String())
+	}
+}
+
+func TestSnapshotRejectsUnknownData(t *testing.T) {
+	world := newTestWorld(t)
+	entity := world.EntityManager.CreateEntity()
+	world.ComponentManager.AddComponent(entity, testPositionType, &testPosition{})
+
+	var saved bytes.Buffer
+	err := world.SaveSnapshot(&saved, SnapshotJSON)
+	if err == nil || !strings.Contains(err.Error(), "not registered") {
+		t.Errorf("Expected an unregistered component type to be reported, got %v", err)
+	}
+
+	err = world.LoadSnapshot(strings.NewReader(`{"Version": 99}`), SnapshotJSON)
+	if err == nil || !strings.Contains(err.Error(), "version 99") {
+		t.Errorf("Expected an unsupported version to be reported, got %v", err)
+	}
+	if !world.ComponentManager.HasComponent(entity, testPositionType) {
+		t.Errorf("Expected a failed load to leave the world as it was")
+	}
+}
+
+func TestSnapshotContinuesRandomNumbers(t *testing.T) {
+	for _, format := range []SnapshotFormat{SnapshotJSON, SnapshotBinary} {
+		world := newTestWorld(t)
+		InsertResource(world, NewRand(42))
+		MustGetResource[Rand](world).Uint64()
+
+		var saved bytes.Buffer
+		if err := world.SaveSnapshot(&saved, format); err != nil {
+			t.Fatalf("Format %d: expected the world to save, got %v", format, err)
+		}
+		expected := MustGetResource[Rand](world).Uint64()
+
+		restored := newTestWorld(t)
+		if err := restored.LoadSnapshot(&saved, format); err != nil {
+			t.Fatalf("Format %d: expected the snapshot to load, got %v", format, err)
+		}
+		random := MustGetResource[Rand](restored)
+		if random.Seed() != 42 {
+			t.Errorf("Format %d: expected seed 42, got %d", format, random.Seed())
+		}
+		if got := random.Uint64(); got != expected {
+			t.Errorf("Format %d: expected the next number to be %d, got %d", format, expected, got)
+		}
+	}
+}
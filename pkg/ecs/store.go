@@ -0,0 +1,90 @@
+package ecs
+
+import "fmt"
+
+// Store is a typed view over all components of a single type.
+// T is the component struct type; *T must implement ComponentInterface.
+// Components are kept in the world's ComponentManager, so stores and the
+// ComponentType based API can be used side by side.
+type Store[T any] struct {
+	cm            *ComponentManager
+	componentType ComponentType
+}
+
+// NewStore creates a typed store for T and registers its component type
+// with the world, so a store never has to be registered separately
+func NewStore[T any](world *World) *Store[T] {
+	componentType := ComponentTypeOf[T]()
+	RegisterComponent[T](world)
+	return &Store[T]{
+		cm:            world.ComponentManager,
+		componentType: componentType,
+	}
+}
+
+// ComponentTypeOf returns the ComponentType reported by T.
+// It panics if *T does not implement ComponentInterface.
+func ComponentTypeOf[T any]() ComponentType {
+	component, ok := any(new(T)).(ComponentInterface)
+	if !ok {
+		panic(fmt.Sprintf("ecs: %T does not implement ComponentInterface", new(T)))
+	}
+	return component.GetType()
+}
+
+// Type returns the component type stored by this store
+func (s *Store[T]) Type() ComponentType {
+	return s.componentType
+}
+
+// Add attaches the component to the entity, replacing any existing one
+func (s *Store[T]) Add(entity Entity, component *T) {
+	s.cm.AddComponent(entity, s.componentType, any(component).(ComponentInterface))
+}
+
+// Get returns the entity's component, or false if it doesn't have one
+func (s *Store[T]) Get(entity Entity) (*T, bool) {
+	component, found := s.cm.GetComponent(entity, s.componentType)
+	if !found {
+		return nil, false
+	}
+	return any(component).(*T), true
+}
+
+// GetMut returns the entity's component for modification, recording it as changed
+// at the current tick. Unlike MarkChanged it doesn't call the OnChange hooks,
+// since the component hasn't been modified yet.
+func (s *Store[T]) GetMut(entity Entity) (*T, bool) {
+	component, found := s.Get(entity)
+	if found {
+		s.cm.markTick(entity, s.componentType)
+	}
+	return component, found
+}
+
+// Has reports whether the entity has a component of this type
+func (s *Store[T]) Has(entity Entity) bool {
+	return s.cm.HasComponent(entity, s.componentType)
+}
+
+// Remove detaches the component from the entity
+func (s *Store[T]) Remove(entity Entity) {
+	s.cm.RemoveComponent(entity, s.componentType)
+}
+
+// MarkChanged reports that the entity's component was modified in place
+func (s *Store[T]) MarkChanged(entity Entity) {
+	s.cm.MarkChanged(entity, s.componentType)
+}
+
+// Each calls fn for every entity that has a component of this type
+func (s *Store[T]) Each(fn func(entity Entity, component *T)) {
+	for entity, component := range s.cm.components[s.componentType] {
+		fn(entity, any(component).(*T))
+	}
+}
+
+// Len returns the number of entities with a component of this type
+func (s *Store[T]) Len() int {
+	return len(s.cm.components[s.componentType])
+}
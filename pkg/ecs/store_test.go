@@ -0,0 +1,71 @@
+package ecs
+
+import (
+	"log"
+	"testing"
+)
+
+const testPositionType ComponentType = "test_position"
+
+type testPosition struct {
+	Component
+	X, Y float64
+}
+
+func (c testPosition) GetType() ComponentType {
+	return testPositionType
+}
+
+func newTestWorld(t *testing.T) *World {
+	return NewWorld(log.New(log.Writer(), t.Name()+": ", log.Flags()))
+}
+
+func TestStoreSharesComponentManager(t *testing.T) {
+	world := newTestWorld(t)
+	positions := NewStore[testPosition](world)
+
+	if positions.Type() != testPositionType {
+		t.Fatalf("Expected store type %q, got %q", testPositionType, positions.Type())
+	}
+
+	// Components added through the store are visible to the string API
+	a := world.EntityManager.CreateEntity()
+	positions.Add(a, &testPosition{X: 1, Y: 2})
+	if !world.ComponentManager.HasComponent(a, testPositionType) {
+		t.Errorf("Expected entity %d to have %q through the ComponentManager", a, testPositionType)
+	}
+
+	// Components added through the string API are visible to the store
+	b := world.EntityManager.CreateEntity()
+	world.ComponentManager.AddComponent(b, testPositionType, &testPosition{X: 3, Y: 4})
+	pos, found := positions.Get(b)
+	if !found || pos.X != 3 || pos.Y != 4 {
+		t.Errorf("Expected position {3 4} for entity %d, got %v (found %v)", b, pos, found)
+	}
+
+	if positions.Len() != 2 {
+		t.Errorf("Expected 2 positions, got %d", positions.Len())
+	}
+
+	sum := 0.0
+	positions.Each(func(entity Entity, pos *testPosition) {
+		sum += pos.X
+	})
+	if sum != 4 {
+		t.Errorf("Expected Each to visit both positions (sum 4), got sum %v", sum)
+	}
+
+	positions.Remove(a)
+	if positions.Has(a) {
+		t.Errorf("Expected entity %d to have no position after Remove", a)
+	}
+}
+
+func TestComponentTypeOfPanicsForNonComponents(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected ComponentTypeOf to panic for a non-component type")
+		}
+	}()
+	ComponentTypeOf[struct{ X int }]()
+}
@@ -1,73 +1,94 @@
 package ecs
 
-import "log"
+import (
+	"log"
+	"reflect"
+	"sync"
+)
 
 // World is the main ECS container that holds all entities, components, and systems
 type World struct {
 	EntityManager    *EntityManager
 	ComponentManager *ComponentManager
-	systems          []System
-	eventQueue       []EventInterface // Simple event queue for communication
-	eventHandlers    map[EventType][]func(EventInterface)
+	schedule         *Schedule
+	commands         *Commands
+	resources        map[reflect.Type]any
+	events           map[reflect.Type]eventChannel
+	pendingEvents    []func() // Subscriber calls queued by Send
+	eventMu          sync.Mutex
+	componentTypes   map[ComponentType]reflect.Type // Concrete types, for snapshots
+	resourceTypes    map[string]reflect.Type        // Resources saved in snapshots, by name
+	batchResources   map[reflect.Type]bool          // Resources declared by the parallel batch running
 	Logger           *log.Logger
 }
 
 func NewWorld(logger *log.Logger) *World {
-	return &World{
+	w := &World{
 		EntityManager:    NewEntityManager(),
 		ComponentManager: NewComponentManager(),
-		systems:          []System{},
-		eventQueue:       []EventInterface{},
-		eventHandlers:    make(map[EventType][]func(EventInterface)),
+		schedule:         NewSchedule(),
+		resources:        make(map[reflect.Type]any),
+		events:           make(map[reflect.Type]eventChannel),
+		componentTypes:   make(map[ComponentType]reflect.Type),
+		resourceTypes:    make(map[string]reflect.Type),
 		Logger:           logger,
 	}
+	w.commands = NewCommands(w)
+
+	InsertResource(w, &Clock{})
+	RegisterResource[Clock](w, "ecs.clock")
+	InsertResource(w, NewRand(0))
+	RegisterResource[Rand](w, "ecs.rand")
+	return w
 }
 
+// AddSystem schedules a system in PhaseUpdate with no ordering constraints
 func (w *World) AddSystem(system System) {
-	w.systems = append(w.systems, system)
+	w.schedule.Add(system, SystemOptions{})
 }
 
-func (w *World) RemoveEntity(entity Entity) {
-	w.EntityManager.RemoveEntity(entity)
-	w.ComponentManager.RemoveAllComponents(entity)
+// AddSystemWithOptions schedules a system with a name, phase, ordering
+// constraints and run condition
+func (w *World) AddSystemWithOptions(system System, options SystemOptions) {
+	w.schedule.Add(system, options)
 }
 
-func (w *World) Update(deltaTime float64) {
-	for _, system := range w.systems {
-		system.Update(w, deltaTime)
-	}
-
-	// Process events after all systems have updated
-	w.processEvents()
+// BuildSchedule checks the system ordering constraints and works out the run
+// order. Call it at startup to report misordered systems before the first Update.
+func (w *World) BuildSchedule() error {
+	return w.schedule.Build()
 }
 
-// Simple event system for communication between ECS and external systems
-type EventType string
+// SetWorkers sets how many systems with non-conflicting declared access may
+// run at the same time. See Schedule.SetWorkers.
+func (w *World) SetWorkers(workers int) {
+	w.schedule.SetWorkers(workers)
+}
 
-type EventInterface interface {
-	Type() EventType
-	Entity() Entity
-	Data() any
+// Schedule returns the world's system schedule
+func (w *World) Schedule() *Schedule {
+	return w.schedule
 }
 
-func (w *World) RegisterEventHandler(eventType EventType, handler func(EventInterface)) {
-	w.eventHandlers[eventType] = append(w.eventHandlers[eventType], handler)
+// Commands returns the world's command buffer for deferred structural changes
+func (w *World) Commands() *Commands {
+	return w.commands
 }
 
-func (w *World) QueueEvent(event EventInterface) {
-	w.eventQueue = append(w.eventQueue, event)
+func (w *World) RemoveEntity(entity Entity) {
+	w.EntityManager.RemoveEntity(entity)
+	w.ComponentManager.RemoveAllComponents(entity)
 }
 
-func (w *World) processEvents() {
-	// Process all events in the queue
-	for _, event := range w.eventQueue {
-		if handlers, exists := w.eventHandlers[event.Type()]; exists {
-			for _, handler := range handlers {
-				handler(event)
-			}
-		}
-	}
+func (w *World) Update(deltaTime float64) {
+	MustGetResource[Clock](w).Advance(deltaTime)
+	w.schedule.Run(w, deltaTime)
+
+	// Notify event subscribers after all systems have updated
+	w.ComponentManager.advanceTick()
+	w.flushEvents()
+	w.commands.Apply()
 
-	// Clear queue
-	w.eventQueue = w.eventQueue[:0]
+	// Readers keep seeing this frame's events for one more frame
+	w.endEventFrame()
 }